@@ -0,0 +1,559 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package avl
+
+import (
+	"fmt"
+
+	"github.com/logrusorgru/gods/bound"
+	"github.com/logrusorgru/gods/printer"
+)
+
+type LessFunc func(a, b interface{}) bool
+
+type EqualFunc func(a, b interface{}) bool
+
+type ZeroFunc func(a interface{}) bool
+
+// Bound of a range of the AscendRange or the DescendRange, use
+// bound.Inclusive, bound.Exclusive or bound.Unbounded.
+type Bound = bound.Bound
+
+type node struct {
+	d, l, r *node
+	h       int8 // height
+	k       interface{}
+	v       interface{}
+}
+
+func newNode(dad *node, k, v interface{}) (n *node) {
+	n = new(node)
+	n.d = dad
+	n.h = 1
+	n.k = k
+	n.v = v
+	return
+}
+
+func (n *node) height() int8 {
+	if n == nil {
+		return 0
+	}
+	return n.h
+}
+
+// difference between heights of left and right subtrees
+func (n *node) balance() int8 {
+	return n.l.height() - n.r.height()
+}
+
+// update height of the node using heights of its children
+func (n *node) fixHeight() {
+	if l, r := n.l.height(), n.r.height(); l > r {
+		n.h = l + 1
+	} else {
+		n.h = r + 1
+	}
+}
+
+// next node in ascending order: min of the right subtree
+// or the first dad the n is at left of
+func (n *node) next() *node {
+	if n.r != nil {
+		for n = n.r; n.l != nil; n = n.l {
+		}
+		return n
+	}
+	for ; n.d != nil; n = n.d {
+		if n.d.l == n {
+			return n.d
+		}
+	}
+	return nil
+}
+
+// prev node in ascending order: max of the left subtree
+// or the first dad the n is at right of
+func (n *node) prev() *node {
+	if n.l != nil {
+		for n = n.l; n.r != nil; n = n.r {
+		}
+		return n
+	}
+	for ; n.d != nil; n = n.d {
+		if n.d.r == n {
+			return n.d
+		}
+	}
+	return nil
+}
+
+func (n *node) replaceChild(old, new *node) {
+	if n.l == old {
+		n.l = new
+	} else {
+		n.r = new
+	}
+}
+
+func (n *node) copy(x *node) {
+	n.k, n.v = x.k, x.v
+}
+
+type Tree struct {
+	r *node
+
+	less  LessFunc
+	equal EqualFunc
+	zero  ZeroFunc
+
+	size int
+}
+
+func New(less LessFunc, equal EqualFunc, zero ZeroFunc) (t *Tree) {
+	t = new(Tree)
+	t.less = less
+	t.equal = equal
+	t.zero = zero
+	return
+}
+
+// findInsertNode finds node to insert to
+func (t *Tree) findInsertNode(d *node, k interface{}) *node {
+	for p, less := d, t.less; p != nil; { // p - place
+		if less(k, p.k) {
+			p, d = p.l, p // left side
+		} else {
+			p, d = p.r, p // right side
+		}
+	}
+	return d
+}
+
+// findNode and its dad
+func (t *Tree) findNode(k interface{}) (d, n *node) {
+	var (
+		less  = t.less
+		equal = t.equal
+	)
+	for n, d = t.r, nil; n != nil; {
+		switch {
+		case equal(k, n.k):
+			return
+		case less(k, n.k):
+			n, d = n.l, n
+		default:
+			n, d = n.r, n
+		}
+	}
+	return
+}
+
+// replace the n with the x in the n.d or in the root
+func (t *Tree) replace(n, x *node) {
+	if x != nil {
+		x.d = n.d
+	}
+	if n.d == nil {
+		t.r = x
+	} else {
+		n.d.replaceChild(n, x)
+	}
+}
+
+func (t *Tree) rightRotate(n *node) (pivot *node) {
+	pivot = n.l
+	t.replace(n, pivot)
+	n.l = pivot.r
+	if pivot.r != nil {
+		pivot.r.d = n
+	}
+	pivot.r, n.d = n, pivot
+	n.fixHeight()
+	pivot.fixHeight()
+	return
+}
+
+func (t *Tree) leftRotate(n *node) (pivot *node) {
+	pivot = n.r
+	t.replace(n, pivot)
+	n.r = pivot.l
+	if pivot.l != nil {
+		pivot.l.d = n
+	}
+	pivot.l, n.d = n, pivot
+	n.fixHeight()
+	pivot.fixHeight()
+	return
+}
+
+// rebalance subtree of the n returning new root of the subtree
+func (t *Tree) rebalance(n *node) *node {
+	n.fixHeight()
+	switch b := n.balance(); {
+	case b > 1: // left heavy
+		if n.l.balance() < 0 {
+			t.leftRotate(n.l) // left right case
+		}
+		return t.rightRotate(n)
+	case b < -1: // right heavy
+		if n.r.balance() > 0 {
+			t.rightRotate(n.r) // right left case
+		}
+		return t.leftRotate(n)
+	}
+	return n
+}
+
+// rebalance the Tree from the n up to the root, it stops
+// when height of a subtree is not changed
+func (t *Tree) retrace(n *node) {
+	for n != nil {
+		var h = n.h
+		if n = t.rebalance(n); n.h == h {
+			return // ancestors are not affected
+		}
+		n = n.d
+	}
+}
+
+// insert node to the tree and add pointer to it
+// to the d
+func (t *Tree) insertNode(d, n *node) {
+	t.size++
+	if d == nil {
+		t.r = n // first element of the tree
+		return  // done
+	}
+	// n already points to the d; required branch
+	// (left or right) is nil and its guarantee by
+	// findInsertNode
+	if t.less(n.k, d.k) {
+		d.l = n // left (less)
+	} else {
+		d.r = n // right (greater or equal)
+	}
+	n.d = d
+	t.retrace(d)
+}
+
+// Ins is insert or overwrite, returning
+//
+//     1. previous value, false
+//     2. nil, true
+//
+// The first case where an existing value overwritten. The
+// second case where created new item.
+func (t *Tree) Ins(k, v interface{}) (p interface{}, ok bool) {
+	var d, n = t.findNode(k)
+	if n != nil {
+		p, n.v = n.v, v
+		return // p, false
+	}
+	// n is nil
+	d = t.findInsertNode(d, k)
+	t.insertNode(d, newNode(d, k, v))
+	return nil, true
+}
+
+// InsNx is insert if does not exist, returning
+//
+//     1. existing value, false
+//     2. nil, true
+//
+// The first case if item already exists. The second case
+// if item created.
+func (t *Tree) InsNx(k, v interface{}) (e interface{}, ok bool) {
+	var d, n = t.findNode(k)
+	if n != nil {
+		return n.v, false // already exists
+	}
+	// n is nil
+	d = t.findInsertNode(d, k)
+	t.insertNode(d, newNode(d, k, v))
+	return nil, true
+}
+
+// InsEx is insert if exists, returning
+//
+//     1. previous value, true
+//     2. nil, false
+//
+// The first case if item already exists and has been overwritten.
+// The second case if item doesn't exist.
+func (t *Tree) InsEx(k, v interface{}) (p interface{}, ok bool) {
+	var _, n = t.findNode(k)
+	if n == nil {
+		return nil, false // does not exist
+	}
+	p, n.v, ok = n.v, v, true
+	return
+}
+
+// Add is add new node even if it already exists. The Add called
+// with the same key many times makes the Tree not unique. The
+// Add returns true if item with given key is first in the Tree,
+// i.e. if the Tree is still unique.
+func (t *Tree) Add(k, v interface{}) (ok bool) {
+	var d, n = t.findNode(k)
+	if n != nil {
+		d = t.findInsertNode(n, k) // found, the Tree is or becomes not unique
+	} else {
+		ok, d = true, t.findInsertNode(d, k) // not found
+	}
+	t.insertNode(d, newNode(d, k, v))
+	return
+}
+
+// delete and balance the Tree
+func (t *Tree) delBalancing(n *node) {
+	if n.l != nil && n.r != nil {
+		var s = n.r // successor, the min of the right
+		for s.l != nil {
+			s = s.l
+		}
+		n.copy(s)
+		n = s // delete the successor instead
+	}
+	// the n has at most one child
+	var c = n.l
+	if c == nil {
+		c = n.r
+	}
+	t.replace(n, c)
+	t.retrace(n.d)
+}
+
+// Get value by key. It returns (nil, false) if the
+// Tree doesn't contain element with given key. If
+// the Tree is not unique, the Get return first
+// element. Use the Ascend or the Descend to get all
+// non-unique elements.
+func (t *Tree) Get(k interface{}) (v interface{}, ok bool) {
+	var _, n = t.findNode(k)
+	if n != nil {
+		return n.v, true // got it
+	}
+	return nil, false // not found
+}
+
+func (t *Tree) Del(k interface{}) (v interface{}, ok bool) {
+	var _, n = t.findNode(k)
+	if n == nil {
+		return nil, false // does not exist
+	}
+	v, ok = n.v, true
+	t.size--          //reduce
+	t.delBalancing(n) // delete & balance
+	return
+}
+
+func (t *Tree) minNode() (n *node) {
+	if t.r == nil {
+		return
+	}
+	for n = t.r; n.l != nil; n = n.l {
+	}
+	return
+}
+
+func (t *Tree) maxNode() (n *node) {
+	if t.r == nil {
+		return
+	}
+	for n = t.r; n.r != nil; n = n.r {
+	}
+	return
+}
+
+func (t *Tree) Min() (k, v interface{}, ok bool) {
+	if n := t.minNode(); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+func (t *Tree) Max() (k, v interface{}, ok bool) {
+	if n := t.maxNode(); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+func (t *Tree) Size() int {
+	return t.size
+}
+
+func (t *Tree) Clear() {
+	t.size, t.r = 0, nil
+}
+
+// A WalkFunc is iterator. If it
+// returns false iteration stops.
+type WalkFunc func(k, v interface{}) (next bool)
+
+func pop(ns []*node) (xs []*node, n *node) {
+	if len(ns) == 0 {
+		return ns, nil
+	}
+	n, xs = ns[len(ns)-1], ns[:len(ns)-1]
+	return
+}
+
+func walk(n *node, walkFunc WalkFunc) bool {
+	if n == nil {
+		return true
+	}
+	return walkFunc(n.k, n.v) && walk(n.l, walkFunc) && walk(n.r, walkFunc)
+}
+
+// Walk elements of the Tree without any order.
+func (t *Tree) Walk(walkFunc WalkFunc) {
+	walk(t.r, walkFunc) // recursive
+}
+
+// (-inf, +inf)
+func (t *Tree) ascend(ascendFunc WalkFunc) {
+	for n := t.minNode(); n != nil; {
+		if !ascendFunc(n.k, n.v) {
+			return
+		}
+		n = n.next()
+	}
+}
+
+// the first node of a range of the lower bound
+func (t *Tree) lowerNode(lo Bound) (s *node) {
+	for n, less := t.r, t.less; n != nil; {
+		if lo.Below(less, n.k) {
+			n = n.r
+		} else {
+			s, n = n, n.l
+		}
+	}
+	return
+}
+
+// the zero key is unbounded, other keys are inclusive
+func (t *Tree) zeroBound(k interface{}) Bound {
+	if t.zero(k) {
+		return bound.Unbounded
+	}
+	return bound.Inclusive(k)
+}
+
+// AscendRange iterates elements of the Tree in ascending order
+// from the lower bound from to the upper bound to. Unlike the
+// Ascend, a bound can be any key, including zero one.
+func (t *Tree) AscendRange(from, to Bound, ascendFunc WalkFunc) {
+	var less = t.less
+	for n := t.lowerNode(from); n != nil; n = n.next() {
+		if to.Above(less, n.k) {
+			return // that's all
+		}
+		if !ascendFunc(n.k, n.v) {
+			return
+		}
+	}
+}
+
+// Ascend iterates elements of the tree ascending order. The ZeroFunc
+// used to determine ascending range: a zero from or to is unbounded,
+// other keys are inclusive bounds. See also the AscendRange.
+func (t *Tree) Ascend(from, to interface{}, ascendFunc WalkFunc) {
+	t.AscendRange(t.zeroBound(from), t.zeroBound(to), ascendFunc)
+}
+
+// (-inf, +inf) (reversed)
+func (t *Tree) descend(descendFunc WalkFunc) {
+	for n := t.maxNode(); n != nil; {
+		if !descendFunc(n.k, n.v) {
+			return
+		}
+		n = n.prev()
+	}
+}
+
+// the last node of a range of the upper bound
+func (t *Tree) upperNode(hi Bound) (s *node) {
+	for n, less := t.r, t.less; n != nil; {
+		if hi.Above(less, n.k) {
+			n = n.l
+		} else {
+			s, n = n, n.r
+		}
+	}
+	return
+}
+
+// DescendRange iterates elements of the Tree in descending order
+// from the upper bound from to the lower bound to. Unlike the
+// Descend, a bound can be any key, including zero one.
+func (t *Tree) DescendRange(from, to Bound, descendFunc WalkFunc) {
+	var less = t.less
+	for n := t.upperNode(from); n != nil; n = n.prev() {
+		if to.Below(less, n.k) {
+			return // that's all
+		}
+		if !descendFunc(n.k, n.v) {
+			return
+		}
+	}
+}
+
+// Descend iterates elements of the tree descending order. The
+// ZeroFunc used to determine descending range like the Ascend
+// does. See also the DescendRange.
+func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
+	t.DescendRange(t.zeroBound(from), t.zeroBound(to), descendFunc)
+}
+
+// Printer prints a Tree, use printer.New
+// to print to string
+type Printer = printer.Printer
+
+// print subtree of the n
+func (n *node) print(p Printer) {
+	p = p.Add(fmt.Sprintf("%v (h=%d)", n.k, n.h))
+	if n.l == nil && n.r == nil {
+		return
+	}
+	for _, c := range []*node{n.l, n.r} {
+		if c == nil {
+			p.Add("nil") // keep left and right
+			continue
+		}
+		c.print(p)
+	}
+}
+
+// Print the Tree to given Printer. Labels of nodes
+// are keys and heights, like "5 (h=2)".
+func (t *Tree) Print(p Printer) {
+	if t.r != nil {
+		t.r.print(p)
+	}
+}
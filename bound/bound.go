@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+// Package bound implements bounds of ranges of trees. A bound
+// of a range is unbounded, inclusive or exclusive key, thus any
+// key, including zero one, can be a bound.
+package bound
+
+type kind uint8
+
+const (
+	unbounded kind = iota // the zero Bound
+	inclusive
+	exclusive
+)
+
+// A Bound is lower or upper bound of a range of keys.
+// The zero Bound is Unbounded.
+type Bound struct {
+	kind kind
+	key  interface{}
+}
+
+// Unbounded range has no the bound.
+var Unbounded Bound
+
+// Inclusive bound of a range that contains given key.
+func Inclusive(key interface{}) Bound {
+	return Bound{inclusive, key}
+}
+
+// Exclusive bound of a range that doesn't contain given key.
+func Exclusive(key interface{}) Bound {
+	return Bound{exclusive, key}
+}
+
+// IsUnbounded returns true if the Bound is Unbounded.
+func (b Bound) IsUnbounded() bool {
+	return b.kind == unbounded
+}
+
+// IsInclusive returns true if the Bound is inclusive.
+func (b Bound) IsInclusive() bool {
+	return b.kind == inclusive
+}
+
+// IsExclusive returns true if the Bound is exclusive.
+func (b Bound) IsExclusive() bool {
+	return b.kind == exclusive
+}
+
+// Key of the Bound, it's nil for Unbounded.
+func (b Bound) Key() interface{} {
+	return b.key
+}
+
+// Below returns true if given key is below a range
+// the Bound is lower bound of. The less compares keys.
+func (b Bound) Below(less func(x, y interface{}) bool, key interface{}) bool {
+	switch b.kind {
+	case inclusive:
+		return less(key, b.key)
+	case exclusive:
+		return !less(b.key, key)
+	}
+	return false
+}
+
+// Above returns true if given key is above a range
+// the Bound is upper bound of. The less compares keys.
+func (b Bound) Above(less func(x, y interface{}) bool, key interface{}) bool {
+	switch b.kind {
+	case inclusive:
+		return less(b.key, key)
+	case exclusive:
+		return !less(key, b.key)
+	}
+	return false
+}
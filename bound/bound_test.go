@@ -0,0 +1,63 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package bound
+
+import (
+	"testing"
+)
+
+func less(x, y interface{}) bool {
+	return x.(int) < y.(int)
+}
+
+func TestBound(t *testing.T) {
+	for _, tc := range []struct {
+		b            Bound
+		below, above [3]bool // for keys 4, 5 and 6
+	}{
+		{Unbounded, [3]bool{}, [3]bool{}},
+		{Inclusive(5), [3]bool{true, false, false}, [3]bool{false, false, true}},
+		{Exclusive(5), [3]bool{true, true, false}, [3]bool{false, true, true}},
+	} {
+		for i, key := range []int{4, 5, 6} {
+			if got := tc.b.Below(less, key); got != tc.below[i] {
+				t.Errorf("%v: below %d: %t", tc.b, key, got)
+			}
+			if got := tc.b.Above(less, key); got != tc.above[i] {
+				t.Errorf("%v: above %d: %t", tc.b, key, got)
+			}
+		}
+	}
+	if !Unbounded.IsUnbounded() || Unbounded.Key() != nil {
+		t.Error("wrong Unbounded")
+	}
+	if b := Inclusive(0); !b.IsInclusive() || b.IsUnbounded() || b.Key() != 0 {
+		t.Error("wrong inclusive zero")
+	}
+	if b := Exclusive(0); !b.IsExclusive() || b.IsUnbounded() || b.Key() != 0 {
+		t.Error("wrong exclusive zero")
+	}
+}
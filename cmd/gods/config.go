@@ -0,0 +1,176 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/logrusorgru/gods/gen"
+)
+
+// a config is loaded configuration file
+type config struct {
+	path    string         // path to the file
+	entries []*gen.Options // structures
+}
+
+// entry name used in errors
+func (c *config) entryName(i int) string {
+	var e = c.entries[i]
+	if e.Structure == "" {
+		return fmt.Sprintf("%s: structure #%d", c.path, i)
+	}
+	if gen.Lookup(e.Structure) == nil {
+		return fmt.Sprintf("%s: structure #%d (%s)", c.path, i, e.Structure)
+	}
+	return fmt.Sprintf("%s: structure #%d (%s %s)", c.path, i, e.Structure,
+		e.Prefix+e.Tree)
+}
+
+// wrap given error of i-th entry
+func (c *config) entryErr(i int, err error) error {
+	if oe, ok := err.(*gen.OptionError); ok {
+		return fmt.Errorf("%s: field %q: %v", c.entryName(i), oe.Option,
+			oe.Err)
+	}
+	return fmt.Errorf("%s: %v", c.entryName(i), err)
+}
+
+// load configuration file that is JSON array of structures, like
+//
+//	[
+//		{
+//			"kind": "rbtree",
+//			"type": "int",
+//			"value": "string",
+//			"tree": "IntTree",
+//			"package": "mypkg",
+//			"output": "int_tree.go",
+//			"thread-safe": true
+//		}
+//	]
+//
+// Relative output paths are relative to the file.
+func loadConfig(path string) (c *config, err error) {
+	var data []byte
+	if data, err = ioutil.ReadFile(path); err != nil {
+		return
+	}
+	var raws []json.RawMessage
+	if err = json.Unmarshal(data, &raws); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	c = &config{path: path}
+	for i, raw := range raws {
+		var kind struct {
+			Kind string `json:"kind"`
+		}
+		c.entries = append(c.entries, &gen.Options{})
+		if err = json.Unmarshal(raw, &kind); err != nil {
+			return nil, c.entryErr(i, err)
+		}
+		c.entries[i].Structure = kind.Kind
+		var g = gen.Lookup(kind.Kind)
+		if g == nil {
+			return nil, c.entryErr(i, &gen.OptionError{Option: "kind",
+				Err: fmt.Errorf("unknown structure %q", kind.Kind)})
+		}
+		var entry = new(gen.Options)
+		g.Flags(flag.NewFlagSet(kind.Kind, flag.ContinueOnError), entry) // set defaults
+		var dec = json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(entry); err != nil {
+			return nil, c.entryErr(i, err)
+		}
+		if entry.Output == "" {
+			return nil, c.entryErr(i, &gen.OptionError{Option: "output",
+				Err: fmt.Errorf("missing output file")})
+		}
+		entry.RelativeTo(filepath.Dir(path))
+		c.entries[i] = entry
+	}
+	return
+}
+
+// generate all structures of the config, it returns
+// generated files or all errors
+func (c *config) generate() (files []gen.File, errs []error) {
+	for i, e := range c.entries {
+		var fs, err = gen.GenerateFiles(*e)
+		if err != nil {
+			errs = append(errs, c.entryErr(i, err))
+			continue
+		}
+		files = append(files, fs...)
+	}
+	return
+}
+
+func genConfig(args []string) {
+
+	var (
+		path    string
+		verbose bool
+		out     emitter
+	)
+
+	set := flag.NewFlagSet("gods", flag.ExitOnError)
+	out.flags(set)
+	set.StringVar(&path,
+		"config",
+		"gods.json",
+		"configuration file, JSON array of structures")
+	set.BoolVar(&verbose,
+		"v",
+		false,
+		"print names of generated files")
+	set.Parse(args)
+
+	var c, err = loadConfig(path)
+	fatal(err)
+
+	var files, errs = c.generate()
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, "gods:", err)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+
+	for _, f := range files {
+		fatal(out.emit(f.Path, f.Src))
+		if verbose {
+			fmt.Println(f.Path)
+		}
+	}
+	out.exit()
+}
@@ -0,0 +1,212 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// number of context lines of a unified diff
+const diffContext = 3
+
+// an edit is a line of a diff
+type edit struct {
+	op   byte // ' ', '-' or '+'
+	line string
+}
+
+// split text to lines keeping line endings
+func splitLines(text []byte) (lines []string) {
+	lines = strings.SplitAfter(string(text), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return
+}
+
+// shortest edit script transforming the a to the b, it's
+// the linear space variant of the Myers algorithm: find
+// middle snake of the shortest path and diff the parts
+// before and after the snake
+func diffLines(a, b []string) (es []edit) {
+	return appendDiff(nil, a, b)
+}
+
+// append edit script of the a and the b to the es
+func appendDiff(es []edit, a, b []string) []edit {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		es, a, b = append(es, edit{' ', a[0]}), a[1:], b[1:]
+	}
+	var s int // length of common suffix
+	for s < len(a) && s < len(b) && a[len(a)-1-s] == b[len(b)-1-s] {
+		s++
+	}
+	var suffix = a[len(a)-s:]
+	a, b = a[:len(a)-s], b[:len(b)-s]
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			es = append(es, edit{'+', line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			es = append(es, edit{'-', line})
+		}
+	default:
+		var x, y, u, v = middleSnake(a, b)
+		es = appendDiff(es, a[:x], b[:y])
+		for _, line := range a[x:u] {
+			es = append(es, edit{' ', line})
+		}
+		es = appendDiff(es, a[u:], b[v:])
+	}
+	for _, line := range suffix {
+		es = append(es, edit{' ', line})
+	}
+	return es
+}
+
+// middle snake (x, y) - (u, v) of the shortest edit script,
+// forward paths go from the beginning of the a and the b and
+// reverse paths go from the end; a snake where they overlap
+// is a part of the shortest path
+func middleSnake(a, b []string) (x, y, u, v int) {
+	var (
+		n, m  = len(a), len(b)
+		delta = n - m
+		max   = (n + m + 1) / 2
+		off   = max + 1
+		fv    = make([]int, 2*max+3) // forward x by diagonal x - y
+		rv    = make([]int, 2*max+3) // reverse x, counted from the end
+	)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && fv[off+k-1] < fv[off+k+1]) {
+				x = fv[off+k+1] // down, insertion
+			} else {
+				x = fv[off+k-1] + 1 // right, deletion
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u, v = u+1, v+1
+			}
+			fv[off+k] = u
+			// reverse diagonal of the k is delta - k
+			if r := delta - k; delta%2 != 0 && r >= -(d-1) && r <= d-1 &&
+				u+rv[off+r] >= n {
+				return
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var rx int
+			if k == -d || (k != d && rv[off+k-1] < rv[off+k+1]) {
+				rx = rv[off+k+1]
+			} else {
+				rx = rv[off+k-1] + 1
+			}
+			var ry, sx = rx - k, rx
+			for sx < n && sx-k < m && a[n-1-sx] == b[m-1-(sx-k)] {
+				sx++
+			}
+			rv[off+k] = sx
+			if f := delta - k; delta%2 == 0 && f >= -d && f <= d &&
+				fv[off+f]+sx >= n {
+				return n - sx, m - (sx - k), n - rx, m - ry
+			}
+		}
+	}
+	return // unreachable
+}
+
+// unifiedDiff returns unified diff of given texts
+// or nil if they are equal
+func unifiedDiff(aName, bName string, a, b []byte) []byte {
+	if bytes.Equal(a, b) {
+		return nil
+	}
+	var (
+		es  = diffLines(splitLines(a), splitLines(b))
+		buf bytes.Buffer
+	)
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", aName, bName)
+
+	// line numbers (zero based) in the a and in the b of each edit
+	var as, bs = make([]int, len(es)+1), make([]int, len(es)+1)
+	for i, e := range es {
+		as[i+1], bs[i+1] = as[i], bs[i]
+		if e.op != '+' {
+			as[i+1]++
+		}
+		if e.op != '-' {
+			bs[i+1]++
+		}
+	}
+
+	for i := 0; i < len(es); {
+		if es[i].op == ' ' {
+			i++
+			continue
+		}
+		// hunk starts with a change, extend it while
+		// next change is close enough
+		var start, end = i - diffContext, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(es) && j-end <= 2*diffContext; j++ {
+			if es[j].op != ' ' {
+				end = j
+			}
+		}
+		end += diffContext + 1
+		if end > len(es) {
+			end = len(es)
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(as[start], as[end]-as[start]),
+			hunkRange(bs[start], bs[end]-bs[start]))
+		for _, e := range es[start:end] {
+			buf.WriteByte(e.op)
+			buf.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return buf.Bytes()
+}
+
+// range of a hunk, the start is zero based
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
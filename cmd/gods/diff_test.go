@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		a, b string
+		diff string
+	}{
+		{"empty", "", "", ""},
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{"to empty", "a\nb\n", "", "--- a\n+++ b\n" +
+			"@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"from empty", "", "a\nb\n", "--- a\n+++ b\n" +
+			"@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"insert", "a\nb\nc\n", "a\nb\nx\nc\n", "--- a\n+++ b\n" +
+			"@@ -1,3 +1,4 @@\n a\n b\n+x\n c\n"},
+		{"delete", "a\nb\nc\n", "a\nc\n", "--- a\n+++ b\n" +
+			"@@ -1,3 +1,2 @@\n a\n-b\n c\n"},
+		{"mixed", "a\nb\nc\nd\n", "a\nx\nc\nd\ny\n", "--- a\n+++ b\n" +
+			"@@ -1,4 +1,5 @@\n a\n-b\n+x\n c\n d\n+y\n"},
+		{"no newline", "a\nb", "a\nc", "--- a\n+++ b\n" +
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n" +
+			"+c\n\\ No newline at end of file\n"},
+		{"hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n", "--- a\n+++ b\n" +
+				"@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n" +
+				"@@ -7,4 +8,3 @@\n 7\n 8\n 9\n-10\n"},
+	} {
+		var diff = string(unifiedDiff("a", "b", []byte(tt.a), []byte(tt.b)))
+		if diff != tt.diff {
+			t.Errorf("%s: wrong diff\n%s\nwant\n%s", tt.name, diff, tt.diff)
+		}
+	}
+}
+
+// length of longest common subsequence
+func lcs(a, b []string) int {
+	var l = make([][]int, len(a)+1)
+	for i := range l {
+		l[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				l[i][j] = l[i+1][j+1] + 1
+			case l[i+1][j] > l[i][j+1]:
+				l[i][j] = l[i+1][j]
+			default:
+				l[i][j] = l[i][j+1]
+			}
+		}
+	}
+	return l[0][0]
+}
+
+func TestDiffLines(t *testing.T) {
+	// edit scripts of random texts transform the a to the b
+	// and are the shortest ones
+	var rnd = rand.New(rand.NewSource(1))
+	var text = func() (lines []string) {
+		for i := rnd.Intn(40); i > 0; i-- {
+			lines = append(lines, string(rune('a'+rnd.Intn(4))))
+		}
+		return
+	}
+	for i := 0; i < 2000; i++ {
+		var (
+			a, b   = text(), text()
+			ga, gb []string
+			n      int // number of changes
+		)
+		for _, e := range diffLines(a, b) {
+			if e.op != '+' {
+				ga = append(ga, e.line)
+			}
+			if e.op != '-' {
+				gb = append(gb, e.line)
+			}
+			if e.op != ' ' {
+				n++
+			}
+		}
+		if strings.Join(ga, "") != strings.Join(a, "") ||
+			strings.Join(gb, "") != strings.Join(b, "") {
+			t.Fatal("wrong edit script", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); n != want {
+			t.Fatal("not shortest edit script", a, b, n, "want", want)
+		}
+	}
+}
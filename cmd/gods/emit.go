@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// an emitter writes generated sources or
+// compares them with existing files
+type emitter struct {
+	check bool      // compare with existing files, don't write
+	diff  bool      // the same as the check
+	stale int       // number of stale files found
+	w     io.Writer // diffs of stale files, os.Stdout if nil
+}
+
+// register flags of the emitter
+func (e *emitter) flags(set *flag.FlagSet) {
+	set.BoolVar(&e.check,
+		"check",
+		false,
+		"don't write, print unified diff and exit with error "+
+			"if an output file is stale")
+	set.BoolVar(&e.diff,
+		"diff",
+		false,
+		"the same as the -check")
+}
+
+// emit generated source, write it to given file or
+// to stdout if the output is empty
+func (e *emitter) emit(output string, src []byte) (err error) {
+	if !e.check && !e.diff {
+		return writeOutput(output, src)
+	}
+	if output == "" {
+		return errors.New("-check and -diff require output file")
+	}
+	var old []byte
+	if old, err = ioutil.ReadFile(output); os.IsNotExist(err) {
+		err = nil // stale, diff with empty file
+	} else if err != nil {
+		return
+	}
+	var diff = unifiedDiff(output, output+" (generated)", old, src)
+	if diff == nil {
+		return nil // up to date
+	}
+	e.stale++
+	fmt.Fprintln(os.Stderr, "gods:", output, "is stale")
+	var w = e.w
+	if w == nil {
+		w = os.Stdout
+	}
+	_, err = w.Write(diff)
+	return
+}
+
+// exit with error if stale files found
+func (e *emitter) exit() {
+	if e.stale > 0 {
+		os.Exit(1)
+	}
+}
+
+// write generated source to given file or
+// to stdout if the output is empty
+func writeOutput(output string, src []byte) (err error) {
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return
+	}
+	return ioutil.WriteFile(output, src, 0644)
+}
+
+// exit with error if it's not nil
+func fatal(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gods:", err)
+		os.Exit(1)
+	}
+}
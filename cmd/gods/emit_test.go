@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmitter_check(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "gods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		path = filepath.Join(dir, "tree.go")
+		buf  bytes.Buffer
+		e    = emitter{check: true, w: &buf}
+	)
+	if err = ioutil.WriteFile(path, []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name  string
+		path  string
+		src   string
+		stale int
+		diff  string
+	}{
+		{"up to date", path, "a\nb\n", 0, ""},
+		{"stale", path, "a\nc\n", 1, "--- " + path + "\n+++ " + path +
+			" (generated)\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"},
+		{"missing", path + ".nope", "a\n", 2, "--- " + path + ".nope\n" +
+			"+++ " + path + ".nope (generated)\n@@ -0,0 +1,1 @@\n+a\n"},
+	} {
+		buf.Reset()
+		if err = e.emit(tt.path, []byte(tt.src)); err != nil {
+			t.Fatal(tt.name, err)
+		}
+		if e.stale != tt.stale {
+			t.Errorf("%s: wrong number of stale files %d, want %d", tt.name,
+				e.stale, tt.stale)
+		}
+		if buf.String() != tt.diff {
+			t.Errorf("%s: wrong diff\n%s\nwant\n%s", tt.name, buf.String(),
+				tt.diff)
+		}
+	}
+	// doesn't write
+	var src []byte
+	if src, err = ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != "a\nb\n" {
+		t.Error("file changed by -check")
+	}
+	if _, err = os.Stat(path + ".nope"); !os.IsNotExist(err) {
+		t.Error("file created by -check")
+	}
+}
+
+func TestDirective_check(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "gods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var file = filepath.Join(dir, "a.go")
+	err = ioutil.WriteFile(file, []byte("package p\n\n"+
+		"//gods:rbtree -type int -value string -tree IntTree\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ds []*directive
+	if ds, err = scanDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 {
+		t.Fatal("wrong number of directives", len(ds))
+	}
+	var generate = func(e *emitter) {
+		t.Helper()
+		var seen = make(map[string]token.Position)
+		if _, err := ds[0].generate(e, seen); err != nil {
+			t.Fatal(err)
+		}
+	}
+	generate(new(emitter)) // write
+	var (
+		buf    bytes.Buffer
+		e      = emitter{check: true, w: &buf}
+		output = filepath.Join(dir, "inttree_gods.go")
+		src    []byte
+	)
+	generate(&e)
+	if e.stale != 0 || buf.Len() != 0 {
+		t.Fatal("generated file is stale", buf.String())
+	}
+	if src, err = ioutil.ReadFile(output); err != nil {
+		t.Fatal(err)
+	}
+	src = bytes.Replace(src, []byte("\npackage p\n"),
+		[]byte("\npackage p // x\n"), 1)
+	if err = ioutil.WriteFile(output, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	generate(&e)
+	if e.stale != 1 {
+		t.Fatal("changed file is not stale")
+	}
+	if !strings.Contains(buf.String(), "-package p // x\n+package p\n") {
+		t.Error("wrong diff", buf.String())
+	}
+}
@@ -0,0 +1,234 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/logrusorgru/gods/gen"
+)
+
+// prefix of a comment that requests a structure, like
+//
+//	//gods:rbtree -type int -value string
+const directivePrefix = "//gods:"
+
+// a directive is a structure requested by a comment
+type directive struct {
+	pos  token.Position // position of the comment
+	pkg  string         // package of the file
+	name string         // name of the structure
+	args []string       // command line arguments
+}
+
+func genGenerate(args []string) {
+
+	var (
+		verbose bool
+		out     emitter
+		seen    = make(map[string]token.Position) // output -> directive
+	)
+
+	set := flag.NewFlagSet("generate", flag.ExitOnError)
+	out.flags(set)
+	set.BoolVar(&verbose,
+		"v",
+		false,
+		"print names of generated files")
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), `Usage: %s generate [-v] [-check] [-diff] [packages]
+
+Generate structures requested by '%s' comments of Go files of
+given packages. The packages are directories and the 'dir/...'
+pattern means the directory and all its subdirectories. Default
+is current directory. For example
+
+    %srbtree -type int -value string -tree IntTree
+
+The comment is the same as command line arguments. The output
+file is placed next to the file of the comment. Default package
+is package of the file and default output is lowercased name of
+the tree with '_gods.go' suffix. Directives of the same output are
+error, name their trees or outputs.
+
+`, os.Args[0], directivePrefix, directivePrefix)
+		set.PrintDefaults()
+	}
+	set.Parse(args)
+
+	var patterns = set.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	var dirs, err = expandPatterns(patterns)
+	fatal(err)
+
+	for _, dir := range dirs {
+		var ds []*directive
+		ds, err = scanDir(dir)
+		fatal(err)
+		for _, d := range ds {
+			var outputs []string
+			outputs, err = d.generate(&out, seen)
+			fatal(err)
+			if verbose {
+				fmt.Println(strings.Join(outputs, "\n"))
+			}
+		}
+	}
+	out.exit()
+}
+
+// expand 'dir/...' patterns to list of directories
+func expandPatterns(patterns []string) (dirs []string, err error) {
+	for _, pattern := range patterns {
+		if pattern != "..." && !strings.HasSuffix(pattern, "/...") {
+			dirs = append(dirs, pattern)
+			continue
+		}
+		var root = strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+		err = filepath.Walk(root,
+			func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.IsDir() {
+					return nil
+				}
+				if path != root && skipDir(info.Name()) {
+					return filepath.SkipDir
+				}
+				dirs = append(dirs, path)
+				return nil
+			})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// skip hidden, testdata and vendor directories like the go tool does
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+		name == "testdata" || name == "vendor"
+}
+
+// find all directives of Go files of given directory
+func scanDir(dir string) (ds []*directive, err error) {
+	var infos []os.FileInfo
+	if infos, err = ioutil.ReadDir(dir); err != nil {
+		return
+	}
+	var fset = token.NewFileSet()
+	for _, info := range infos {
+		var name = info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		var fds []*directive
+		if fds, err = scanFile(fset, filepath.Join(dir, name)); err != nil {
+			return
+		}
+		ds = append(ds, fds...)
+	}
+	return
+}
+
+// find all directives of given Go file
+func scanFile(fset *token.FileSet, path string) (ds []*directive, err error) {
+	var file, perr = parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if perr != nil {
+		return nil, perr
+	}
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, directivePrefix) {
+				continue
+			}
+			var d = &directive{
+				pos: fset.Position(comment.Pos()),
+				pkg: file.Name.Name,
+			}
+			var line = strings.TrimPrefix(comment.Text, directivePrefix)
+			if d.args, err = gen.SplitArgs(line); err != nil {
+				return nil, fmt.Errorf("%s: %v", d.pos, err)
+			}
+			if len(d.args) == 0 {
+				return nil, fmt.Errorf("%s: missing name of structure", d.pos)
+			}
+			d.name, d.args = d.args[0], d.args[1:]
+			ds = append(ds, d)
+		}
+	}
+	return
+}
+
+// generate structure of the directive, it returns
+// paths to the generated files; the seen are outputs
+// of previous directives
+func (d *directive) generate(out *emitter,
+	seen map[string]token.Position) (outputs []string, err error) {
+	var opts gen.Options
+	if opts, err = parseOptions(d.name, d.args); err != nil {
+		return nil, fmt.Errorf("%s: %v", d.pos, err)
+	}
+	if opts.Package == "" {
+		opts.Package = d.pkg
+	}
+	if opts.Output == "" {
+		opts.Output = strings.ToLower(opts.Prefix+opts.Tree) + "_gods.go"
+	}
+	opts.RelativeTo(filepath.Dir(d.pos.Filename))
+	if pos, ok := seen[opts.Output]; ok {
+		return nil, fmt.Errorf("%s: output %s is generated by %s too, "+
+			"use -o or -tree", d.pos, opts.Output, pos)
+	}
+	seen[opts.Output] = d.pos
+	var fs []gen.File
+	if fs, err = gen.GenerateFiles(opts); err != nil {
+		return nil, fmt.Errorf("%s: %v", d.pos, err)
+	}
+	for _, f := range fs {
+		if err = out.emit(f.Path, f.Src); err != nil {
+			return
+		}
+		outputs = append(outputs, f.Path)
+	}
+	return
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/logrusorgru/gods/gen"
+)
+
+// a structureInfo describes a structure for editors and IDEs
+type structureInfo struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Options     []optionInfo `json:"options"`
+}
+
+// an optionInfo describes an option of a structure
+type optionInfo struct {
+	Flag    string      `json:"flag"`    // name of the flag
+	Field   string      `json:"field"`   // field of configuration file
+	Type    string      `json:"type"`    // bool, string or list
+	Default interface{} `json:"default"` // default value
+	Usage   string      `json:"usage"`
+}
+
+// describe given generator
+func describe(g *gen.Generator) (info structureInfo) {
+	info.Name, info.Description = g.Name, g.Description
+	info.Options = []optionInfo{} // not null
+	var set = flag.NewFlagSet(g.Name, flag.ContinueOnError)
+	g.Flags(set, new(gen.Options))
+	set.VisitAll(func(f *flag.Flag) {
+		var o = optionInfo{
+			Flag:    f.Name,
+			Field:   f.Name,
+			Type:    "string",
+			Default: f.DefValue,
+			Usage:   f.Usage,
+		}
+		if o.Field == "o" {
+			o.Field = "output"
+		}
+		switch v := f.Value.(type) {
+		case *gen.Strings:
+			o.Type, o.Default = "list", append([]string{}, *v...)
+		case interface{ IsBoolFlag() bool }:
+			if v.IsBoolFlag() {
+				o.Type, o.Default = "bool", f.DefValue == "true"
+			}
+		}
+		info.Options = append(info.Options, o)
+	})
+	return
+}
+
+func genList(args []string) {
+
+	var asJSON bool
+
+	set := flag.NewFlagSet("list", flag.ExitOnError)
+	set.BoolVar(&asJSON,
+		"json",
+		false,
+		"describe structures and their options in JSON")
+	set.Parse(args)
+
+	var gs = gen.Generators()
+	if !asJSON {
+		for _, g := range gs {
+			fmt.Printf("%-10s %s\n", g.Name, g.Description)
+		}
+		return
+	}
+
+	var infos = make([]structureInfo, 0, len(gs))
+	for _, g := range gs {
+		infos = append(infos, describe(g))
+	}
+	var enc = json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	enc.SetEscapeHTML(false)
+	fatal(enc.Encode(infos))
+}
@@ -26,75 +26,87 @@
 package main
 
 import (
-	"flags"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strings"
-)
 
-const version = "1.0"
+	"github.com/logrusorgru/gods/gen"
+)
 
 func showHelp(out io.Writer, code int) {
 	fmt.Fprintf(out, `The Gods is a generator of Golang data structures
 
 Supported data structures:
 
-    rbtree     Red-black tree
-    avltree    AVL-tree
+`)
+	for _, g := range gen.Generators() {
+		fmt.Fprintf(out, "    %-10s %s\n", g.Name, g.Description)
+	}
+	fmt.Fprintf(out, `
+Commands:
+
+    generate   generate structures requested by //gods: comments
+    regen      regenerate files using their headers
+    templates  dump built-in templates to customize generated code
+    list       list data structures and their options
     version    show generator version
 
 Use '%s help [data structure]' for details.
-`, os.Args[0])
+
+Use '%s -config gods.json' to generate all structures
+described by a configuration file.
+`, os.Args[0], os.Args[0])
 	os.Exit(code)
 }
 
+// show help of given structure
+func showTreeHelp(name string) {
+	var g = gen.Lookup(name)
+	if g == nil {
+		showHelp(os.Stderr, 1)
+	}
+	var set = flag.NewFlagSet(name, flag.ContinueOnError)
+	set.SetOutput(os.Stdout)
+	g.Flags(set, new(gen.Options))
+	treeUsage(set, g)
+}
+
 func main() {
 
 	if len(os.Args) < 2 {
 		showHelp(os.Stderr, 1)
 	}
 
-	switch strings.ToLower(os.Args[1]) {
-	case "rbtree":
-		genRBTree(os.Args[1:])
-	case "avltree":
-		genAVLTree(os.Args[1:])
+	if strings.HasPrefix(os.Args[1], "-") {
+		genConfig(os.Args[1:]) // gods -config gods.json
+		return
+	}
+
+	switch name := strings.ToLower(os.Args[1]); name {
+	case "generate":
+		genGenerate(os.Args[2:])
+	case "regen":
+		genRegen(os.Args[2:])
+	case "templates":
+		genTemplates(os.Args[2:])
+	case "list":
+		genList(os.Args[2:])
 	case "version":
-		fmt.Println("gods", version)
+		fmt.Println("gods", gen.Version)
 	case "help":
+		if len(os.Args) > 2 {
+			showTreeHelp(strings.ToLower(os.Args[2]))
+			return
+		}
 		showHelp(os.Stdout, 0)
-	}
-
-}
-
-// Strings represents list of strings
-type Strings []string
-
-// String implements flag.Value interface
-func (s Strings) String() (ss string) {
-	for _, x := range s {
-		ss += x + ","
-	}
-	if len(ss) > 0 {
-		ss = ss[len(ss)-1:] // trim trailing comma
-	}
-	return
-}
-
-// Set implements flag.Value interface
-func (s *Strings) Set(val string) (_ error) {
-	*s = append(*s, val)
-	return
-}
-
-// Contain returns true if the
-// Strings contain given element
-func (s Strings) Contain(name string) bool {
-	for _, x := range s {
-		if x == name {
-			return true
+	default:
+		if g := gen.Lookup(name); g != nil {
+			genTree(g, os.Args[2:])
+			return
 		}
+		showHelp(os.Stderr, 1)
 	}
-	return false
+
 }
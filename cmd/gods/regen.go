@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/logrusorgru/gods/gen"
+)
+
+// regenerate given file using its header, it returns
+// generated files
+func regenerate(path string) (fs []gen.File, err error) {
+	var src []byte
+	if src, err = ioutil.ReadFile(path); err != nil {
+		return
+	}
+	var (
+		args []string
+		opts gen.Options
+	)
+	if args, err = gen.Command(src); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if opts, err = parseOptions(args[0], args[1:]); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	opts.RelativeTo(filepath.Dir(path))
+	if fs, err = gen.GenerateFiles(opts); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if opts.Output == "" {
+		fs[0].Path = path // generated to stdout
+	}
+	return
+}
+
+func genRegen(args []string) {
+
+	var (
+		verbose bool
+		out     emitter
+	)
+
+	set := flag.NewFlagSet("regen", flag.ExitOnError)
+	out.flags(set)
+	set.BoolVar(&verbose,
+		"v",
+		false,
+		"print names of generated files")
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), `Usage: %s regen [-v] [-check] [-diff] file.go...
+
+Regenerate given files using the current generator. A generated
+file begins with the version of the generator and the command
+line producing the file, relative paths of the command line are
+relative to the file. A file with tests regenerates the tree too.
+
+`, os.Args[0])
+		set.PrintDefaults()
+	}
+	set.Parse(args)
+
+	if set.NArg() == 0 {
+		set.Usage()
+		os.Exit(2)
+	}
+
+	var seen = make(map[string]bool)
+	for _, path := range set.Args() {
+		if seen[filepath.Clean(path)] {
+			continue // regenerated with other file
+		}
+		var fs, err = regenerate(path)
+		fatal(err)
+		for _, f := range fs {
+			seen[filepath.Clean(f.Path)] = true
+			fatal(out.emit(f.Path, f.Src))
+			if verbose {
+				fmt.Println(f.Path)
+			}
+		}
+	}
+	out.exit()
+}
@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/logrusorgru/gods/gen"
+)
+
+func genTemplates(args []string) {
+	if len(args) == 0 || args[0] != "dump" {
+		fmt.Fprintln(os.Stderr, "gods: usage: gods templates dump [-f] [dir]")
+		os.Exit(1)
+	}
+
+	var force bool
+
+	set := flag.NewFlagSet("templates dump", flag.ExitOnError)
+	set.BoolVar(&force,
+		"f",
+		false,
+		"overwrite existing files")
+	set.Usage = func() {
+		fmt.Fprintf(set.Output(), `Usage: %s templates dump [-f] [dir]
+
+Write built-in templates to given directory (default is current
+directory) as a starting point of a -templates directory.
+
+Files of the directory are common.tmpl with blocks shared by all
+structures, tests.tmpl with tests of the -tests flag and a file
+for every structure, like rbtree.tmpl. A file of a -templates
+directory overrides only blocks it defines; remove blocks you
+don't change to keep them up to date with the generator. A file
+with content outside of blocks replaces the file template. The
+empty "extra" and "extraTests" blocks are for additional methods
+and their tests.
+
+Flags:
+
+`, os.Args[0])
+		set.PrintDefaults()
+	}
+	set.Parse(args[1:])
+
+	var dir = "."
+	switch set.NArg() {
+	case 0:
+	case 1:
+		dir = set.Arg(0)
+	default:
+		set.Usage()
+		os.Exit(2)
+	}
+
+	fatal(os.MkdirAll(dir, 0755))
+	var seen = make(map[string]bool)
+	for _, g := range gen.Generators() {
+		for _, tf := range gen.Templates(g.Name) {
+			if seen[tf.Name] {
+				continue // the common and the tests
+			}
+			seen[tf.Name] = true
+			fatal(dumpTemplate(filepath.Join(dir, tf.Name+".tmpl"), tf.Text,
+				force))
+		}
+	}
+}
+
+// write template to given file
+func dumpTemplate(path, text string, force bool) (err error) {
+	if !force {
+		if _, err = os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists, use -f to overwrite", path)
+		} else if !os.IsNotExist(err) {
+			return
+		}
+	}
+	return ioutil.WriteFile(path, []byte(text), 0644)
+}
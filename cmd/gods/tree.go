@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/logrusorgru/gods/gen"
+)
+
+// generate structure using command line arguments
+func genTree(g *gen.Generator, args []string) {
+	var (
+		opts = gen.Options{Structure: g.Name}
+		set  = flag.NewFlagSet(g.Name, flag.ExitOnError)
+		out  emitter
+	)
+	g.Flags(set, &opts)
+	out.flags(set)
+	set.Usage = func() { treeUsage(set, g) }
+	set.Parse(args)
+	var fs, err = g.Generate(opts)
+	fatal(err)
+	for _, f := range fs {
+		fatal(out.emit(f.Path, f.Src))
+	}
+	out.exit()
+}
+
+// print usage of given structure
+func treeUsage(set *flag.FlagSet, g *gen.Generator) {
+	fmt.Fprintf(set.Output(), `Usage: %s %s [flags]
+
+%s generator.
+
+Flags:
+
+`, os.Args[0], g.Name, g.Description)
+	set.PrintDefaults()
+}
+
+// options of given structure from command line arguments
+func parseOptions(name string, args []string) (opts gen.Options, err error) {
+	var g = gen.Lookup(name)
+	if g == nil {
+		return opts, fmt.Errorf("unknown structure %q", name)
+	}
+	opts.Structure = name
+	var set = flag.NewFlagSet(name, flag.ContinueOnError)
+	set.SetOutput(ioutil.Discard)
+	g.Flags(set, &opts)
+	if err = set.Parse(args); err != nil {
+		return
+	}
+	if set.NArg() > 0 {
+		err = fmt.Errorf("unexpected arguments %q", set.Args())
+	}
+	return
+}
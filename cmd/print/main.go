@@ -27,8 +27,8 @@ package main
 
 import (
 	"fmt"
-	"github.com/disiqueira/gotree"
-	"github.com/kr/pretty"
+
+	"github.com/logrusorgru/gods/printer"
 	"github.com/logrusorgru/gods/rb/rb"
 )
 
@@ -46,14 +46,6 @@ func newNatural() *rb.Tree {
 	)
 }
 
-type Tree struct {
-	gotree.Tree
-}
-
-func (t *Tree) Add(text string) rb.Printer {
-	return &Tree{t.Tree.Add(text)}
-}
-
 func main() {
 	var tree = newNatural()
 
@@ -61,11 +53,10 @@ func main() {
 		tree.Ins(i, 0)
 	}
 
-	var pt = &Tree{gotree.New("rb-tree")}
+	var pt = printer.New("rb-tree")
 	tree.Print(pt)
 
-	fmt.Println(pt.Print())
-	// pretty.Println(tree)
+	fmt.Println(pt)
 
 	var fizz = []int{
 		1,
@@ -89,32 +80,29 @@ func main() {
 			tree.Del(i)
 		}
 		{
-			var pt = &Tree{gotree.New("rb-tree")}
+			var pt = printer.New("rb-tree")
 			tree.Print(pt)
-			fmt.Println(pt.Print())
+			fmt.Println(pt)
 		}
 		for _, i := range fizz {
 			tree.Ins(i, 0)
 		}
 		{
-			var pt = &Tree{gotree.New("rb-tree")}
+			var pt = printer.New("rb-tree")
 			tree.Print(pt)
-			fmt.Println(pt.Print())
+			fmt.Println(pt)
 		}
 	}
 
-	_ = pretty.Print
-
 	{
 		for i := 0; i <= 20; i++ {
 			tree.Del(i)
 		}
 
-		var pt = &Tree{gotree.New("rb-tree")}
+		var pt = printer.New("rb-tree")
 		tree.Print(pt)
 
-		fmt.Println(pt.Print())
-		// pretty.Println(tree)
+		fmt.Println(pt)
 	}
 
 }
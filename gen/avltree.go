@@ -0,0 +1,402 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+const avlTreeTemplate = `{{ template "header" . }}
+
+{{ template "node" . }}
+
+{{ template "zero" . }}
+
+{{ template "bound" . }}
+
+{{ template "tree" . }}
+
+{{ template "find" . }}
+
+{{ template "balance" . }}
+
+{{ template "ins" . }}
+
+{{ template "delete" . }}
+
+{{ template "access" . }}
+
+{{ template "walk" . }}
+
+{{ template "count" . }}
+
+{{ template "print" . }}
+
+{{ template "extra" . }}
+
+{{- define "node" }}
+type node struct {
+{{- if .Stacked }}
+	l, r *node
+{{- else }}
+	d, l, r *node
+{{- end }}
+	h       int8 // height
+{{- if .Counted }}
+	s       int  // size of the subtree
+{{- end }}
+	k       {{ .Type }}
+{{- if .KeyValue }}
+	v       {{ .Value }}
+{{- end }}
+}
+{{ if .Stacked }}
+func newNode({{ params }}) (n *node) {
+	n = new(node)
+{{- else }}
+func newNode(dad *node, {{ params }}) (n *node) {
+	n = new(node)
+	n.d = dad
+{{- end }}
+	n.h = 1
+{{- if .Counted }}
+	n.s = 1
+{{- end }}
+	n.k = k
+{{- if .KeyValue }}
+	n.v = v
+{{- end }}
+	return
+}
+
+func (n *node) height() int8 {
+	if n == nil {
+		return 0
+	}
+	return n.h
+}
+
+// difference between heights of left and right subtrees
+func (n *node) balance() int8 {
+	return n.l.height() - n.r.height()
+}
+
+// update height of the node using heights of its children
+func (n *node) fixHeight() {
+	if l, r := n.l.height(), n.r.height(); l > r {
+		n.h = l + 1
+	} else {
+		n.h = r + 1
+	}
+}
+
+func (n *node) replaceChild(old, new *node) {
+	if n.l == old {
+		n.l = new
+	} else {
+		n.r = new
+	}
+}
+
+func (n *node) copy(x *node) {
+	n.k = x.k
+{{- if .KeyValue }}
+	n.v = x.v
+{{- end }}
+}
+{{ if .Printer }}
+// label of the n for the Print, like "5 (h=2)"
+func (n *node) label() string {
+	return fmt.Sprintf("%v (h=%d)", n.k, n.h)
+}
+{{ end }}
+{{- end }}
+
+{{- define "balance" }}
+{{- if .Stacked }}
+// replace the n with the x in its dad or in the root,
+// the st is ancestors of the n
+func (t *{{ .TreeType }}) replace(st []*node, n, x *node) {
+	if _, d := pop(st); d == nil {
+		t.r = x
+	} else {
+		d.replaceChild(n, x)
+	}
+}
+
+func (t *{{ .TreeType }}) rightRotate(st []*node, n *node) (pivot *node) {
+	pivot = n.l
+	t.replace(st, n, pivot)
+	n.l, pivot.r = pivot.r, n
+	n.fixHeight()
+	pivot.fixHeight()
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+	return
+}
+
+func (t *{{ .TreeType }}) leftRotate(st []*node, n *node) (pivot *node) {
+	pivot = n.r
+	t.replace(st, n, pivot)
+	n.r, pivot.l = pivot.l, n
+	n.fixHeight()
+	pivot.fixHeight()
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+	return
+}
+
+// rebalance subtree of the n returning new root of the
+// subtree, the st is ancestors of the n
+func (t *{{ .TreeType }}) rebalance(st []*node, n *node) *node {
+	n.fixHeight()
+	switch b := n.balance(); {
+	case b > 1: // left heavy
+		if n.l.balance() < 0 {
+			t.leftRotate(append(st, n), n.l) // left right case
+		}
+		return t.rightRotate(st, n)
+	case b < -1: // right heavy
+		if n.r.balance() > 0 {
+			t.rightRotate(append(st, n), n.r) // right left case
+		}
+		return t.leftRotate(st, n)
+	}
+	return n
+}
+
+// rebalance the tree from the n up to the root, it stops
+// when height of a subtree is not changed; the st is
+// ancestors of the n
+func (t *{{ .TreeType }}) retrace(st []*node, n *node) {
+	for n != nil {
+		var h = n.h
+		if n = t.rebalance(st, n); n.h == h {
+			return // ancestors are not affected
+		}
+		st, n = pop(st)
+	}
+}
+
+// insert node to the tree and add pointer to it
+// to the d, the st is ancestors of the d
+func (t *{{ .TreeType }}) insertNode(st []*node, d, n *node) {
+	t.size++
+	if d == nil {
+		t.r = n // first element of the tree
+		return  // done
+	}
+	// required branch (left or right) is nil and
+	// its guarantee by findInsertNode
+	if {{ less "n.k" "d.k" }} {
+		d.l = n // left (less)
+	} else {
+		d.r = n // right (greater or equal)
+	}
+{{- if .Counted }}
+	addCount(st, 1)
+	d.s++
+{{- end }}
+	t.retrace(st, d)
+}
+{{- else }}
+// replace the n with the x in the n.d or in the root
+func (t *{{ .TreeType }}) replace(n, x *node) {
+	if x != nil {
+		x.d = n.d
+	}
+	if n.d == nil {
+		t.r = x
+	} else {
+		n.d.replaceChild(n, x)
+	}
+}
+
+func (t *{{ .TreeType }}) rightRotate(n *node) (pivot *node) {
+	pivot = n.l
+	t.replace(n, pivot)
+	n.l = pivot.r
+	if pivot.r != nil {
+		pivot.r.d = n
+	}
+	pivot.r, n.d = n, pivot
+	n.fixHeight()
+	pivot.fixHeight()
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+	return
+}
+
+func (t *{{ .TreeType }}) leftRotate(n *node) (pivot *node) {
+	pivot = n.r
+	t.replace(n, pivot)
+	n.r = pivot.l
+	if pivot.l != nil {
+		pivot.l.d = n
+	}
+	pivot.l, n.d = n, pivot
+	n.fixHeight()
+	pivot.fixHeight()
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+	return
+}
+
+// rebalance subtree of the n returning new root of the subtree
+func (t *{{ .TreeType }}) rebalance(n *node) *node {
+	n.fixHeight()
+	switch b := n.balance(); {
+	case b > 1: // left heavy
+		if n.l.balance() < 0 {
+			t.leftRotate(n.l) // left right case
+		}
+		return t.rightRotate(n)
+	case b < -1: // right heavy
+		if n.r.balance() > 0 {
+			t.rightRotate(n.r) // right left case
+		}
+		return t.leftRotate(n)
+	}
+	return n
+}
+
+// rebalance the tree from the n up to the root, it stops
+// when height of a subtree is not changed
+func (t *{{ .TreeType }}) retrace(n *node) {
+	for n != nil {
+		var h = n.h
+		if n = t.rebalance(n); n.h == h {
+			return // ancestors are not affected
+		}
+		n = n.d
+	}
+}
+
+// insert node to the tree and add pointer to it
+// to the d
+func (t *{{ .TreeType }}) insertNode(d, n *node) {
+	t.size++
+	if d == nil {
+		t.r = n // first element of the tree
+		return  // done
+	}
+	// required branch (left or right) is nil and
+	// its guarantee by findInsertNode
+	if {{ less "n.k" "d.k" }} {
+		d.l = n // left (less)
+	} else {
+		d.r = n // right (greater or equal)
+	}
+	n.d = d
+{{- if .Counted }}
+	addCount(d, 1)
+{{- end }}
+	t.retrace(d)
+}
+{{- end }}
+{{ end }}
+
+{{- define "delete" }}
+{{- if .Stacked }}
+// delete and balance the tree, the st is ancestors of the n
+func (t *{{ .TreeType }}) delBalancing(st []*node, n *node) {
+	if n.l != nil && n.r != nil {
+		st = append(st, n)
+		var s = n.r // successor, the min of the right
+		for s.l != nil {
+			st = append(st, s)
+			s = s.l
+		}
+		n.copy(s)
+		n = s // delete the successor instead
+	}
+	// the n has at most one child
+	var c = n.l
+	if c == nil {
+		c = n.r
+	}
+	t.replace(st, n, c)
+{{- if .Counted }}
+	addCount(st, -1)
+{{- end }}
+	var d *node
+	st, d = pop(st)
+	t.retrace(st, d)
+}
+{{- else }}
+// delete and balance the tree
+func (t *{{ .TreeType }}) delBalancing(n *node) {
+	if n.l != nil && n.r != nil {
+		var s = n.r // successor, the min of the right
+		for s.l != nil {
+			s = s.l
+		}
+		n.copy(s)
+		n = s // delete the successor instead
+	}
+	// the n has at most one child
+	var c = n.l
+	if c == nil {
+		c = n.r
+	}
+	t.replace(n, c)
+{{- if .Counted }}
+	addCount(n.d, -1)
+{{- end }}
+	t.retrace(n.d)
+}
+{{- end }}
+{{ end }}
+
+{{- define "check" }}
+// testCheckBalance checks heights and balance of the tree
+func (t *{{ .TreeType }}) testCheckBalance(tb testing.TB) {
+	tb.Helper()
+	var height func(n *node) int8
+	height = func(n *node) (h int8) {
+		if n == nil {
+			return 0
+		}
+		var l, r = height(n.l), height(n.r)
+		if l-r > 1 || r-l > 1 {
+			tb.Fatal("unbalanced node")
+		}
+		if h = l + 1; r > l {
+			h = r + 1
+		}
+		if h != n.h {
+			tb.Fatal("wrong height", n.h, "want", h)
+		}
+		return
+	}
+	height(t.r)
+}
+{{ end }}
+`
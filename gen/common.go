@@ -0,0 +1,901 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+// The commonTemplate contains blocks shared by all trees
+// with parent references. A tree template must provide
+// node type with
+//
+//	d, l, r *node // dad, left and right
+//	k       // key
+//	v       // value (for key-value trees)
+//
+// fields and newNode(dad, k[, v]) constructor and following
+// methods
+//
+//	insertNode(d, n *node) // insert new node n to the d
+//	delBalancing(n *node)  // delete node n
+//
+// and the "check" block with testCheckBalance(tb testing.TB)
+// method for tests. A left-leaning tree provides insertNode(n
+// *node) and deleteNode(k) returning value of the deleted node
+// instead. A node of a tree with the -print has label() method.
+// The "extra" block is empty, a user can override it to add
+// methods (see the -templates flag).
+const commonTemplate = `
+{{ define "generated" -}}
+// Code generated by gods {{ .Version }}; DO NOT EDIT.
+// {{ .Command }}
+{{ end }}
+
+{{ define "header" -}}
+{{ template "generated" . }}
+package {{ .Package }}
+
+{{ with .ImportList -}}
+import (
+{{- range . }}
+	"{{ . }}"
+{{- end }}
+)
+{{- end }}
+{{ end }}
+
+{{ define "extra" }}{{ end }}
+
+{{ define "print" -}}
+{{ if .Printer -}}
+// A Printer is a node of printed tree. The Add adds child
+// node with given label returning the child.
+type Printer interface {
+	Add(label string) Printer
+}
+
+// a textPrinter is a Printer rendering ASCII tree
+type textPrinter struct {
+	label    string
+	children []*textPrinter
+}
+
+// Add implements Printer interface.
+func (p *textPrinter) Add(label string) Printer {
+	var c = &textPrinter{label: label}
+	p.children = append(p.children, c)
+	return c
+}
+
+// render children of the p, the indent is prefix of their lines
+func (p *textPrinter) render(sb *strings.Builder, indent string) {
+	for i, c := range p.children {
+		var branch, next = "|-- ", "|   "
+		if i == len(p.children)-1 {
+			branch, next = "` + "`" + `-- ", "    " // last child
+		}
+		sb.WriteString(indent)
+		sb.WriteString(branch)
+		sb.WriteString(c.label)
+		sb.WriteByte('\n')
+		c.render(sb, indent+next)
+	}
+}
+
+// print subtree of the n
+func (n *node) print(p Printer) {
+	p = p.Add(n.label())
+	if n.l == nil && n.r == nil {
+		return
+	}
+	for _, c := range []*node{n.l, n.r} {
+		if c == nil {
+			p.Add("nil") // keep left and right
+			continue
+		}
+		c.print(p)
+	}
+}
+
+// Print the {{ .TreeType }} to given printer.
+func (t *{{ .TreeType }}) Print(p Printer) {
+	{{ template "rlock" . -}}
+	if t.r != nil {
+		t.r.print(p)
+	}
+}
+
+// String renders the {{ .TreeType }} using ASCII, like
+//
+//	{{ .TreeType }}
+{{- if eq .Kind "AVL" }}
+//	` + "`" + `-- 2 (h=2)
+//	    |-- 1 (h=1)
+//	    ` + "`" + `-- 3 (h=1)
+{{- else if .LeftLeaning }}
+//	` + "`" + `-- [B] 2
+//	    |-- [B] 1
+//	    ` + "`" + `-- [B] 3
+{{- else }}
+//	` + "`" + `-- [B] 2
+//	    |-- [R] 1
+//	    ` + "`" + `-- [R] 3
+{{- end }}
+func (t *{{ .TreeType }}) String() string {
+	var (
+		p  = &textPrinter{label: "{{ .TreeType }}"}
+		sb strings.Builder
+	)
+	t.Print(p)
+	sb.WriteString(p.label)
+	sb.WriteByte('\n')
+	p.render(&sb, "")
+	return sb.String()
+}
+{{- end }}
+{{ end }}
+
+{{ define "lock" -}}
+{{ if .ThreadSafe -}}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+{{ end -}}
+{{ end -}}
+
+{{ define "rlock" -}}
+{{ if .ThreadSafe -}}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+{{ end -}}
+{{ end -}}
+
+{{ define "zero" -}}
+// is given key zero
+{{- if .Generic }}
+func (t *{{ .TreeType }}) isZero(k K) bool {
+{{- else }}
+func isZero(k {{ .Type }}) bool {
+{{- end }}
+	var zero {{ .Type }}
+	return {{ equal "k" "zero" }}
+}
+{{ end }}
+
+{{ define "bound" -}}
+// A Bound is lower or upper bound of a range of keys of the
+// AscendRange or the DescendRange. The zero Bound is unbounded.
+type Bound{{ if .Generic }}[K any]{{ end }} struct {
+	k         {{ .Type }}
+	bounded   bool // the range has the bound
+	exclusive bool // the range doesn't contain the k
+}
+{{ if not .Generic }}
+// Unbounded range has no the bound.
+var Unbounded Bound
+{{ end }}
+
+// Inclusive bound of a range that contains given key.
+func Inclusive{{ if .Generic }}[K any]{{ end }}(k {{ .Type }}) {{ bound }} {
+	return {{ bound }}{k: k, bounded: true}
+}
+
+// Exclusive bound of a range that doesn't contain given key.
+func Exclusive{{ if .Generic }}[K any]{{ end }}(k {{ .Type }}) {{ bound }} {
+	return {{ bound }}{k: k, bounded: true, exclusive: true}
+}
+{{ end }}
+
+{{ define "tree" -}}
+// A {{ .TreeType }} is {{ .Kind }} tree of {{ .Type }}
+{{- if .KeyValue }} keys and {{ .Value }} values{{ else }} items{{ end }}.
+{{- if .Func }}
+// It compares keys using given function, see also the {{ .New }}.
+{{- else if .Generic }}
+// It compares ordered keys using operators, see also the {{ .New }}Func.
+{{- end }}
+{{- if .ThreadSafe }}
+//
+// The {{ .TreeType }} is safe for concurrent use. Methods that change
+// the {{ .TreeType }} hold write lock, other methods hold read lock.
+// The Walk, Ascend and Descend hold the read lock for the whole
+// iteration. Thus an iteration sees consistent {{ .TreeType }} and
+// concurrent changes wait for its end. A WalkFunc must not call
+// methods of the {{ .TreeType }}: changes deadlock, and reads can
+// deadlock if a writer is waiting. Collect elements and process
+// them after the iteration instead. Keep the WalkFunc short,
+// because it blocks writers.
+{{- end }}
+type {{ .TreeType }}{{ if and .Generic (not .Func) }}[K cmp.Ordered, V any]{{ end }} struct {
+{{- if .ThreadSafe }}
+	mu sync.RWMutex
+{{ end }}
+	r    *node
+	size int
+{{- if .Func }}
+
+	compare func(a, b K) int
+{{- end }}
+}
+{{ if .Func }}
+// {{ .New }}Func creates new empty {{ .TreeType }} using given comparison
+// of keys. The compare returns a negative number if a < b, zero if
+// a == b and a positive number if a > b.
+func {{ .New }}Func[K, V any](compare func(a, b K) int) *{{ .TreeType }}[K, V] {
+	return &{{ .TreeType }}[K, V]{compare: compare}
+}
+{{- else if .Generic }}
+// {{ .New }} creates new empty {{ .TreeType }} of ordered keys.
+func {{ .New }}[K cmp.Ordered, V any]() *{{ .TreeType }}[K, V] {
+	return new({{ .TreeType }}[K, V])
+}
+{{- else }}
+// {{ .New }} creates new empty {{ .TreeType }}.
+func {{ .New }}() (t *{{ .TreeType }}) {
+	return new({{ .TreeType }})
+}
+{{- end }}
+{{ end }}
+
+{{ define "find" -}}
+{{ if .Stacked -}}
+// pop last node of the stack
+func pop(st []*node) ([]*node, *node) {
+	if len(st) == 0 {
+		return st, nil
+	}
+	return st[:len(st)-1], st[len(st)-1]
+}
+{{ if not .LeftLeaning }}
+// findInsertNode finds node to insert to starting from
+// the last node of the st, it returns the node and its
+// ancestors
+func (t *{{ .TreeType }}) findInsertNode(st []*node, k {{ .Type }}) ([]*node, *node) {
+	var p *node
+	for st, p = pop(st); p != nil; { // p - place
+		st = append(st, p)
+		if {{ less "k" "p.k" }} {
+			p = p.l // left side
+		} else {
+			p = p.r // right side
+		}
+	}
+	return pop(st)
+}
+{{ end }}
+// findNode and its ancestors
+func (t *{{ .TreeType }}) findNode(k {{ .Type }}) (st []*node, n *node) {
+	for n = t.r; n != nil; {
+{{- if .Func }}
+		switch c := t.compare(k, n.k); {
+		case c == 0:
+			return
+		case c < 0:
+{{- else }}
+		switch {
+		case {{ equal "k" "n.k" }}:
+			return
+		case {{ less "k" "n.k" }}:
+{{- end }}
+			st, n = append(st, n), n.l
+		default:
+			st, n = append(st, n), n.r
+		}
+	}
+	return
+}
+{{- else -}}
+// findInsertNode finds node to insert to
+func (t *{{ .TreeType }}) findInsertNode(d *node, k {{ .Type }}) *node {
+	for p := d; p != nil; { // p - place
+		if {{ less "k" "p.k" }} {
+			p, d = p.l, p // left side
+		} else {
+			p, d = p.r, p // right side
+		}
+	}
+	return d
+}
+
+// findNode and its dad
+func (t *{{ .TreeType }}) findNode(k {{ .Type }}) (d, n *node) {
+	for n, d = t.r, nil; n != nil; {
+{{- if .Func }}
+		switch c := t.compare(k, n.k); {
+		case c == 0:
+			return
+		case c < 0:
+{{- else }}
+		switch {
+		case {{ equal "k" "n.k" }}:
+			return
+		case {{ less "k" "n.k" }}:
+{{- end }}
+			n, d = n.l, n
+		default:
+			n, d = n.r, n
+		}
+	}
+	return
+}
+{{- end }}
+{{ end }}
+
+{{ define "insert" -}}
+{{ if .LeftLeaning -}}
+	t.insertNode(newNode({{ args }}))
+{{- else if .Stacked -}}
+	var d *node
+	st, d = t.findInsertNode(st, k)
+	t.insertNode(st, d, newNode({{ args }}))
+{{- else -}}
+	d = t.findInsertNode(d, k)
+	t.insertNode(d, newNode(d, {{ args }}))
+{{- end }}
+{{- end }}
+
+{{ define "ins" -}}
+// Ins is insert or overwrite, returning
+//
+//     1. previous {{ if .KeyValue }}value{{ else }}item{{ end }}, false
+//     2. zero, true
+//
+// The first case where an existing {{ if .KeyValue }}value{{ else }}item{{ end }} overwritten. The
+// second case where created new item.
+func (t *{{ .TreeType }}) Ins({{ params }}) (p {{ vtype }}, ok bool) {
+	{{ template "lock" . -}}
+	var {{ path }}, n = t.findNode(k)
+	if n != nil {
+		p, {{ val "n" }} = {{ val "n" }}, {{ arg }}
+		return // p, false
+	}
+	// n is nil
+	{{ template "insert" . }}
+	return p, true
+}
+
+// InsNx is insert if does not exist, returning
+//
+//     1. existing {{ if .KeyValue }}value{{ else }}item{{ end }}, false
+//     2. zero, true
+//
+// The first case if item already exists. The second case
+// if item created.
+func (t *{{ .TreeType }}) InsNx({{ params }}) (e {{ vtype }}, ok bool) {
+	{{ template "lock" . -}}
+	var {{ path }}, n = t.findNode(k)
+	if n != nil {
+		return {{ val "n" }}, false // already exists
+	}
+	// n is nil
+	{{ template "insert" . }}
+	return e, true
+}
+
+// InsEx is insert if exists, returning
+//
+//     1. previous {{ if .KeyValue }}value{{ else }}item{{ end }}, true
+//     2. zero, false
+//
+// The first case if item already exists and has been overwritten.
+// The second case if item doesn't exist.
+func (t *{{ .TreeType }}) InsEx({{ params }}) (p {{ vtype }}, ok bool) {
+	{{ template "lock" . -}}
+	var _, n = t.findNode(k)
+	if n == nil {
+		return // does not exist
+	}
+	p, {{ val "n" }}, ok = {{ val "n" }}, {{ arg }}, true
+	return
+}
+{{ if not .Unique }}
+// Add is add new node even if it already exists. The Add called
+// with the same key many times makes the {{ .TreeType }} not unique. The
+// Add returns true if item with given key is first in the {{ .TreeType }},
+// i.e. if the {{ .TreeType }} is still unique.
+func (t *{{ .TreeType }}) Add({{ params }}) (ok bool) {
+	{{ template "lock" . -}}
+{{- if .LeftLeaning }}
+	var _, n = t.findNode(k)
+	ok = n == nil // the tree is or becomes not unique
+	t.insertNode(newNode({{ args }}))
+{{- else if .Stacked }}
+	var st, n = t.findNode(k)
+	var d *node
+	if n != nil {
+		st, d = t.findInsertNode(append(st, n), k) // found, the tree is or becomes not unique
+	} else {
+		ok = true
+		st, d = t.findInsertNode(st, k) // not found
+	}
+	t.insertNode(st, d, newNode({{ args }}))
+{{- else }}
+	var d, n = t.findNode(k)
+	if n != nil {
+		d = t.findInsertNode(n, k) // found, the tree is or becomes not unique
+	} else {
+		ok, d = true, t.findInsertNode(d, k) // not found
+	}
+	t.insertNode(d, newNode(d, {{ args }}))
+{{- end }}
+	return
+}
+{{ end }}
+{{ end }}
+
+{{ define "access" -}}
+// Get {{ if .KeyValue }}value{{ else }}item{{ end }} by key. It returns (zero, false) if the
+// {{ .TreeType }} doesn't contain element with given key.
+{{- if not .Unique }} If
+// the {{ .TreeType }} is not unique, the Get return first
+// element. Use the Ascend or the Descend to get all
+// non-unique elements.
+{{- end }}
+func (t *{{ .TreeType }}) Get(k {{ .Type }}) (v {{ vtype }}, ok bool) {
+	{{ template "rlock" . -}}
+	var _, n = t.findNode(k)
+	if n != nil {
+		return {{ val "n" }}, true // got it
+	}
+	return // not found
+}
+
+// Del deletes {{ if .KeyValue }}value{{ else }}item{{ end }} by key. It returns deleted {{ if .KeyValue }}value{{ else }}item{{ end }}
+// and true, or (zero, false) if the {{ .TreeType }} doesn't
+// contain element with given key.
+func (t *{{ .TreeType }}) Del(k {{ .Type }}) (v {{ vtype }}, ok bool) {
+	{{ template "lock" . -}}
+{{ if .LeftLeaning -}}
+	var _, n = t.findNode(k)
+	if n == nil {
+		return // does not exist
+	}
+	t.size-- // reduce
+	return t.deleteNode(k), true // the deleted node can differ from the n
+{{- else -}}
+	var {{ if .Stacked }}st{{ else }}_{{ end }}, n = t.findNode(k)
+	if n == nil {
+		return // does not exist
+	}
+	v, ok = {{ val "n" }}, true
+	t.size--                     // reduce
+	t.delBalancing({{ if .Stacked }}st, {{ end }}n) // delete & balance
+	return
+{{- end }}
+}
+
+func (t *{{ .TreeType }}) minNode() (n *node) {
+	if t.r == nil {
+		return
+	}
+	for n = t.r; n.l != nil; n = n.l {
+	}
+	return
+}
+
+func (t *{{ .TreeType }}) maxNode() (n *node) {
+	if t.r == nil {
+		return
+	}
+	for n = t.r; n.r != nil; n = n.r {
+	}
+	return
+}
+{{ if .KeyValue }}
+// Min returns key and value of the minimal element of the
+// {{ .TreeType }}, or (zero, zero, false) if the {{ .TreeType }} is empty.
+func (t *{{ .TreeType }}) Min() (k {{ .Type }}, v {{ .Value }}, ok bool) {
+	{{ template "rlock" . -}}
+	if n := t.minNode(); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Max returns key and value of the maximal element of the
+// {{ .TreeType }}, or (zero, zero, false) if the {{ .TreeType }} is empty.
+func (t *{{ .TreeType }}) Max() (k {{ .Type }}, v {{ .Value }}, ok bool) {
+	{{ template "rlock" . -}}
+	if n := t.maxNode(); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+{{ else }}
+// Min returns minimal item of the {{ .TreeType }}, or
+// (zero, false) if the {{ .TreeType }} is empty.
+func (t *{{ .TreeType }}) Min() (k {{ .Type }}, ok bool) {
+	{{ template "rlock" . -}}
+	if n := t.minNode(); n != nil {
+		k, ok = n.k, true
+	}
+	return
+}
+
+// Max returns maximal item of the {{ .TreeType }}, or
+// (zero, false) if the {{ .TreeType }} is empty.
+func (t *{{ .TreeType }}) Max() (k {{ .Type }}, ok bool) {
+	{{ template "rlock" . -}}
+	if n := t.maxNode(); n != nil {
+		k, ok = n.k, true
+	}
+	return
+}
+{{ end }}
+// Size returns number of elements of the {{ .TreeType }}.
+func (t *{{ .TreeType }}) Size() int {
+	{{ template "rlock" . -}}
+	return t.size
+}
+
+// Clear removes all elements of the {{ .TreeType }}.
+func (t *{{ .TreeType }}) Clear() {
+	{{ template "lock" . -}}
+	t.size, t.r = 0, nil
+}
+{{ end }}
+
+{{ define "walk" -}}
+// A WalkFunc is iterator. If it
+// returns false iteration stops.
+type WalkFunc func({{ params }}) (next bool)
+
+func walk(n *node, walkFunc WalkFunc) bool {
+	if n == nil {
+		return true
+	}
+	return walkFunc({{ pair "n" }}) && walk(n.l, walkFunc) && walk(n.r, walkFunc)
+}
+
+// Walk elements of the {{ .TreeType }} without any order.
+func (t *{{ .TreeType }}) Walk(walkFunc WalkFunc) {
+	{{ template "rlock" . -}}
+	walk(t.r, walkFunc) // recursive
+}
+
+// is given key below a range of the lower bound
+func (t *{{ .TreeType }}) below(lo {{ bound }}, k {{ .Type }}) bool {
+	switch {
+	case !lo.bounded:
+		return false
+	case lo.exclusive:
+		return !({{ less "lo.k" "k" }})
+	}
+	return {{ less "k" "lo.k" }}
+}
+
+// is given key above a range of the upper bound
+func (t *{{ .TreeType }}) above(hi {{ bound }}, k {{ .Type }}) bool {
+	switch {
+	case !hi.bounded:
+		return false
+	case hi.exclusive:
+		return !({{ less "k" "hi.k" }})
+	}
+	return {{ less "hi.k" "k" }}
+}
+
+// the zero key is unbounded, other keys are inclusive
+func (t *{{ .TreeType }}) zeroBound(k {{ .Type }}) {{ bound }} {
+	if {{ if .Generic }}t.{{ end }}isZero(k) {
+		return {{ bound }}{}
+	}
+	return Inclusive(k)
+}
+
+{{ if .Stacked -}}
+// leftmost node of the subtree of the n, the st is
+// ancestors of the n; it returns the node and the
+// ancestors that are greater than the node
+func leftmost(st []*node, n *node) ([]*node, *node) {
+	for n != nil && n.l != nil {
+		st, n = append(st, n), n.l
+	}
+	return st, n
+}
+
+// rightmost node of the subtree of the n, the st is
+// ancestors of the n; it returns the node and the
+// ancestors that are less than the node
+func rightmost(st []*node, n *node) ([]*node, *node) {
+	for n != nil && n.r != nil {
+		st, n = append(st, n), n.r
+	}
+	return st, n
+}
+
+// lowerNode is the first node of a range of the lower
+// bound, it returns the node and its ancestors greater
+// than the node
+func (t *{{ .TreeType }}) lowerNode(lo {{ bound }}) ([]*node, *node) {
+	var st []*node
+	for n := t.r; n != nil; {
+		if t.below(lo, n.k) {
+			n = n.r
+		} else {
+			st, n = append(st, n), n.l
+		}
+	}
+	return pop(st)
+}
+
+// upperNode is the last node of a range of the upper
+// bound, it returns the node and its ancestors less
+// than the node
+func (t *{{ .TreeType }}) upperNode(hi {{ bound }}) ([]*node, *node) {
+	var st []*node
+	for n := t.r; n != nil; {
+		if t.above(hi, n.k) {
+			n = n.l
+		} else {
+			st, n = append(st, n), n.r
+		}
+	}
+	return pop(st)
+}
+
+func (t *{{ .TreeType }}) ascendRange(from, to {{ bound }}, ascendFunc WalkFunc) {
+	for st, n := t.lowerNode(from); n != nil; {
+		if t.above(to, n.k) {
+			return // that's all
+		}
+		if !ascendFunc({{ pair "n" }}) {
+			return
+		}
+		if n.r != nil {
+			st, n = leftmost(st, n.r)
+		} else {
+			st, n = pop(st)
+		}
+	}
+}
+
+func (t *{{ .TreeType }}) descendRange(from, to {{ bound }}, descendFunc WalkFunc) {
+	for st, n := t.upperNode(from); n != nil; {
+		if t.below(to, n.k) {
+			return // that's all
+		}
+		if !descendFunc({{ pair "n" }}) {
+			return
+		}
+		if n.l != nil {
+			st, n = rightmost(st, n.l)
+		} else {
+			st, n = pop(st)
+		}
+	}
+}
+{{- else -}}
+// next node in ascending order: min of the right subtree
+// or the first dad the n is at left of
+func (n *node) next() *node {
+	if n.r != nil {
+		for n = n.r; n.l != nil; n = n.l {
+		}
+		return n
+	}
+	for ; n.d != nil; n = n.d {
+		if n.d.l == n {
+			return n.d
+		}
+	}
+	return nil
+}
+
+// prev node in ascending order: max of the left subtree
+// or the first dad the n is at right of
+func (n *node) prev() *node {
+	if n.l != nil {
+		for n = n.l; n.r != nil; n = n.r {
+		}
+		return n
+	}
+	for ; n.d != nil; n = n.d {
+		if n.d.r == n {
+			return n.d
+		}
+	}
+	return nil
+}
+
+// lowerNode is the first node of a range of the lower bound
+func (t *{{ .TreeType }}) lowerNode(lo {{ bound }}) (s *node) {
+	for n := t.r; n != nil; {
+		if t.below(lo, n.k) {
+			n = n.r
+		} else {
+			s, n = n, n.l
+		}
+	}
+	return
+}
+
+// upperNode is the last node of a range of the upper bound
+func (t *{{ .TreeType }}) upperNode(hi {{ bound }}) (s *node) {
+	for n := t.r; n != nil; {
+		if t.above(hi, n.k) {
+			n = n.l
+		} else {
+			s, n = n, n.r
+		}
+	}
+	return
+}
+
+func (t *{{ .TreeType }}) ascendRange(from, to {{ bound }}, ascendFunc WalkFunc) {
+	for n := t.lowerNode(from); n != nil; n = n.next() {
+		if t.above(to, n.k) {
+			return // that's all
+		}
+		if !ascendFunc({{ pair "n" }}) {
+			return
+		}
+	}
+}
+
+func (t *{{ .TreeType }}) descendRange(from, to {{ bound }}, descendFunc WalkFunc) {
+	for n := t.upperNode(from); n != nil; n = n.prev() {
+		if t.below(to, n.k) {
+			return // that's all
+		}
+		if !descendFunc({{ pair "n" }}) {
+			return
+		}
+	}
+}
+{{- end }}
+
+// AscendRange iterates elements of the {{ .TreeType }} in ascending order
+// from the lower bound from to the upper bound to. Unlike the Ascend,
+// a bound can be any key, including zero one.
+func (t *{{ .TreeType }}) AscendRange(from, to {{ bound }}, ascendFunc WalkFunc) {
+	{{ template "rlock" . -}}
+	t.ascendRange(from, to, ascendFunc)
+}
+
+// Ascend iterates elements of the tree ascending order. A zero
+// from or to means unbounded range from or to respectively,
+// other keys are inclusive bounds. See also the AscendRange.
+func (t *{{ .TreeType }}) Ascend(from, to {{ .Type }}, ascendFunc WalkFunc) {
+	{{ template "rlock" . -}}
+	t.ascendRange(t.zeroBound(from), t.zeroBound(to), ascendFunc)
+}
+
+// DescendRange iterates elements of the {{ .TreeType }} in descending order
+// from the upper bound from to the lower bound to. Unlike the Descend,
+// a bound can be any key, including zero one.
+func (t *{{ .TreeType }}) DescendRange(from, to {{ bound }}, descendFunc WalkFunc) {
+	{{ template "rlock" . -}}
+	t.descendRange(from, to, descendFunc)
+}
+
+// Descend iterates elements of the tree descending order. A zero
+// from or to means unbounded range from or to respectively,
+// other keys are inclusive bounds. See also the DescendRange.
+func (t *{{ .TreeType }}) Descend(from, to {{ .Type }}, descendFunc WalkFunc) {
+	{{ template "rlock" . -}}
+	t.descendRange(t.zeroBound(from), t.zeroBound(to), descendFunc)
+}
+{{ end }}
+
+{{ define "count" -}}
+{{ if .Counted -}}
+// size of subtree of the n
+func (n *node) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.s
+}
+
+// recount size of subtree of the n by its children
+func (n *node) recount() {
+	n.s = n.l.count() + n.r.count() + 1
+}
+{{ if not .LeftLeaning }}
+{{- if .Stacked }}
+// add given delta to sizes of subtrees of the st
+func addCount(st []*node, delta int) {
+	for _, n := range st {
+		n.s += delta
+	}
+}
+{{- else }}
+// add given delta to sizes of subtrees of
+// the n and its ancestors
+func addCount(n *node, delta int) {
+	for ; n != nil; n = n.d {
+		n.s += delta
+	}
+}
+{{- end }}
+{{ end }}
+// number of elements below a range of the lower bound
+func (t *{{ .TreeType }}) countBelow(lo {{ bound }}) (c int) {
+	for n := t.r; n != nil; {
+		if t.below(lo, n.k) {
+			c, n = c+n.l.count()+1, n.r
+		} else {
+			n = n.l
+		}
+	}
+	return
+}
+
+// number of elements above a range of the upper bound
+func (t *{{ .TreeType }}) countAbove(hi {{ bound }}) (c int) {
+	for n := t.r; n != nil; {
+		if t.above(hi, n.k) {
+			c, n = c+n.r.count()+1, n.l
+		} else {
+			n = n.r
+		}
+	}
+	return
+}
+
+// Rank returns number of elements less than given key, that is
+// index of the first element with the key, if the {{ .TreeType }}
+// contains it.
+func (t *{{ .TreeType }}) Rank(k {{ .Type }}) int {
+	{{ template "rlock" . -}}
+	return t.countBelow(Inclusive(k))
+}
+{{ if .KeyValue }}
+// Select returns key and value of the i-th element of the
+// {{ .TreeType }} in ascending order starting from zero, or
+// (zero, zero, false) if the i is out of the {{ .TreeType }}.
+func (t *{{ .TreeType }}) Select(i int) (k {{ .Type }}, v {{ .Value }}, ok bool) {
+{{- else }}
+// Select returns the i-th item of the {{ .TreeType }} in ascending
+// order starting from zero, or (zero, false) if the i is out of
+// the {{ .TreeType }}.
+func (t *{{ .TreeType }}) Select(i int) (k {{ .Type }}, ok bool) {
+{{- end }}
+	{{ template "rlock" . -}}
+	for n := t.r; n != nil; {
+		switch l := n.l.count(); {
+		case i < l:
+			n = n.l
+		case i > l:
+			i, n = i-l-1, n.r
+		default:
+			return {{ if .KeyValue }}n.k, n.v{{ else }}n.k{{ end }}, true
+		}
+	}
+	return
+}
+
+// CountRange returns number of elements in range from the
+// lower bound lo to the upper bound hi.
+func (t *{{ .TreeType }}) CountRange(lo, hi {{ bound }}) (c int) {
+	{{ template "rlock" . -}}
+	if c = t.size - t.countBelow(lo) - t.countAbove(hi); c < 0 {
+		c = 0 // empty range
+	}
+	return
+}
+{{ end -}}
+{{ end }}
+`
@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+// Package gen is the generator of the gods command. It renders data
+// structures using built-in templates, or templates of the
+// Options.Templates directory overriding blocks of the built-in ones.
+//
+//	var src, err = gen.Generate(gen.Options{
+//		Structure: "rbtree",
+//		Type:      "int",
+//		Value:     "string",
+//		Package:   "mypkg",
+//	})
+//
+// Errors of options are *OptionError.
+package gen
+
+import (
+	"flag"
+	"fmt"
+	"text/template"
+)
+
+// Version of the generator
+const Version = "1.0"
+
+// a structure is a tree the generator produces
+type structure struct {
+	name        string   // name, like "rbtree"
+	description string   // like "Red-black tree"
+	kind        string   // kind of the tree, like "red-black"
+	text        string   // template
+	skip        []string // flags the structure doesn't support
+}
+
+// built-in structures
+var structures = []structure{
+	{"rbtree", "Red-black tree", "red-black", rbTreeTemplate, nil},
+	{"avltree", "AVL-tree", "AVL", avlTreeTemplate, []string{"ll"}},
+}
+
+func init() {
+	for _, s := range structures {
+		Register(s.generator())
+	}
+}
+
+// find built-in structure by name
+func lookup(name string) (s structure, ok bool) {
+	for _, s = range structures {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return structure{}, false
+}
+
+// generator of the structure
+func (s structure) generator() *Generator {
+	return &Generator{
+		Name:        s.name,
+		Description: s.description,
+		Flags: func(set *flag.FlagSet, opts *Options) {
+			opts.flags(set, s.skip...)
+		},
+		Generate: func(opts Options) ([]File, error) {
+			if opts.Tree == "" {
+				opts.Tree = "Tree"
+			}
+			var r = &rbTree{Options: opts, kind: s.kind}
+			return r.generateFiles(s.text)
+		},
+	}
+}
+
+// A File is a generated file
+type File struct {
+	Path string // output, empty for stdout
+	Src  []byte // formatted source code
+}
+
+// Generate returns source code of the structure, use
+// the GenerateFiles to get tests of the structure too
+func Generate(opts Options) (src []byte, err error) {
+	var fs []File
+	if fs, err = GenerateFiles(opts); err != nil {
+		return
+	}
+	return fs[0].Src, nil
+}
+
+// GenerateFiles returns the structure and its tests if the
+// Options.Tests is set; empty Options.Tree is the "Tree"
+func GenerateFiles(opts Options) (fs []File, err error) {
+	var g = Lookup(opts.Structure)
+	if g == nil {
+		return nil, optionErr("kind",
+			fmt.Errorf("unknown structure %q", opts.Structure))
+	}
+	return g.Generate(opts)
+}
+
+// generate source code of the tree and its tests
+// if requested using given template
+func (r *rbTree) generateFiles(text string) (fs []File, err error) {
+	r.command = r.commandLine()
+	r.given = r.Options
+	if err = r.validate(); err != nil {
+		return
+	}
+	var tmpl *template.Template
+	if tmpl, err = parse(r.Structure, text, r.funcs(), r.Templates); err != nil {
+		return
+	}
+	if fs, err = r.execute(tmpl); err != nil {
+		return
+	}
+	if err = r.verify(fs); err != nil {
+		return nil, r.blame(text, tmpl, err)
+	}
+	return
+}
+
+// execute parsed templates of the tree and its tests
+func (r *rbTree) execute(tmpl *template.Template) (fs []File, err error) {
+	var (
+		src []byte
+		g   *generics
+		ns  = r.namespace()
+	)
+	if src, err = render(tmpl, r.Structure, r); err != nil {
+		return
+	}
+	if r.Generic {
+		var fsrc []byte
+		if fsrc, err = r.renderFunc(tmpl); err != nil {
+			return
+		}
+		if src, err = mergeFunc(src, fsrc); err != nil {
+			return
+		}
+		if src, g, err = genericize(src); err != nil {
+			return
+		}
+	}
+	if ns != nil {
+		if src, err = ns.rename(src); err != nil {
+			return
+		}
+	}
+	fs = append(fs, File{r.Output, src})
+	if !r.Tests {
+		return
+	}
+	if src, err = render(tmpl, "tests", r); err != nil {
+		return
+	}
+	if r.Generic {
+		if src, err = g.instantiate(src); err != nil {
+			return
+		}
+	}
+	if ns != nil {
+		if src, err = ns.rename(src); err != nil {
+			return
+		}
+	}
+	fs = append(fs, File{r.testOutput(), src})
+	return
+}
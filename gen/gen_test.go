@@ -0,0 +1,234 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	var src, err = Generate(Options{
+		Structure: "rbtree",
+		Type:      "int",
+		Value:     "string",
+		Package:   "mypkg",
+		Output:    "dir/int_tree.go",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "// Code generated by gods " + Version + "; DO NOT EDIT.\n" +
+		"// gods rbtree -o int_tree.go -package mypkg -type int -value string\n" +
+		"\n" +
+		"package mypkg\n"
+	if !strings.HasPrefix(string(src), want) {
+		t.Errorf("wrong header\n%s\nwant\n%s", src[:len(want)], want)
+	}
+	if !strings.Contains(string(src), "\nfunc New() (t *Tree) {\n") {
+		t.Error("missing constructor of default tree")
+	}
+}
+
+func TestGenerateFiles(t *testing.T) {
+	var fs, err = GenerateFiles(Options{
+		Structure: "avltree",
+		Type:      "string",
+		Package:   "mypkg",
+		Output:    "string_tree.go",
+		Tests:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Fatalf("wrong number of files: %d", len(fs))
+	}
+	if fs[0].Path != "string_tree.go" || fs[1].Path != "string_tree_test.go" {
+		t.Errorf("wrong paths: %q, %q", fs[0].Path, fs[1].Path)
+	}
+}
+
+func TestGenerate_errors(t *testing.T) {
+	for _, tt := range []struct {
+		opts   Options
+		option string
+	}{
+		{Options{Structure: "list", Type: "int", Package: "p"}, "kind"},
+		{Options{Structure: "rbtree", Package: "p"}, "type"},
+		{Options{Structure: "rbtree", Type: "int"}, "package"},
+		{Options{Structure: "avltree", Type: "int", Package: "p",
+			LeftLeaning: true}, "ll"},
+		{Options{Structure: "rbtree", Type: "int", Package: "p",
+			Tests: true}, "tests"},
+		{Options{Structure: "rbtree", Type: "int", Package: "p",
+			Comparable: true, Less: "%s > %s"}, "comparable"},
+		{Options{Structure: "rbtree", Type: "int", Package: "p",
+			Tree: "Q'x"}, "tree"},
+		{Options{Structure: "rbtree", Type: "int", Package: "p",
+			Prefix: "1x"}, "prefix"},
+		{Options{Structure: "rbtree", Type: "int", Package: "my-pkg"},
+			"package"},
+	} {
+		var _, err = Generate(tt.opts)
+		var oe *OptionError
+		if !errors.As(err, &oe) {
+			t.Errorf("%s: want *OptionError, got %v", tt.option, err)
+			continue
+		}
+		if oe.Option != tt.option {
+			t.Errorf("wrong option %q, want %q", oe.Option, tt.option)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	var opts = Options{Structure: "rbtree"}
+	opts.Flags(flag.NewFlagSet("rbtree", flag.ContinueOnError))
+	opts.Type, opts.Package = "int", "mypkg"
+	opts.Less, opts.Equal = "%s > %s", "%s == %s"
+	var src, err = Generate(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var args []string
+	if args, err = Command(src); err != nil {
+		t.Fatal(err)
+	}
+	var want = []string{"rbtree", "-equal", "%s == %s", "-less", "%s > %s",
+		"-package", "mypkg", "-type", "int"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("wrong command %q, want %q", args, want)
+	}
+	if _, err = Command([]byte("package mypkg\n")); err == nil {
+		t.Error("missing error")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		args []string
+	}{
+		{"", nil},
+		{" a  b\t", []string{"a", "b"}},
+		{`-less '%s < %s' -x ""`, []string{"-less", "%s < %s", "-x", ""}},
+		{`a\ b "c \"d\"" 'e'\''f'`, []string{"a b", `c "d"`, "e'f"}},
+	} {
+		var args, err = SplitArgs(tt.line)
+		if err != nil {
+			t.Errorf("%q: %v", tt.line, err)
+		} else if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%q: got %q, want %q", tt.line, args, tt.args)
+		}
+	}
+	if _, err := SplitArgs(`'a`); err == nil {
+		t.Error("missing error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	var names []string
+	for _, g := range Generators() {
+		names = append(names, g.Name)
+	}
+	if want := []string{"rbtree", "avltree"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("wrong generators %q, want %q", names, want)
+	}
+	var set = flag.NewFlagSet("avltree", flag.ContinueOnError)
+	Lookup("avltree").Flags(set, new(Options))
+	if set.Lookup("ll") != nil || set.Lookup("type") == nil {
+		t.Error("wrong flags of avltree")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("missing panic")
+		}
+	}()
+	Register(&Generator{Name: "rbtree"})
+}
+
+func TestGenerate_verify(t *testing.T) {
+	var dir = t.TempDir()
+	const pkg = "package mypkg\n\ntype T struct{ a int }\n\ntype node struct{}\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "pkg.go"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var tpl = filepath.Join(dir, "tpl")
+	if err := os.Mkdir(tpl, 0755); err != nil {
+		t.Fatal(err)
+	}
+	const extra = `{{ define "extraTests" }}
+func TestNope(t *testing.T) { nope() }
+{{ end }}
+`
+	if err := ioutil.WriteFile(filepath.Join(tpl, "rbtree.tmpl"), []byte(extra), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var output = filepath.Join(dir, "tree.go")
+	for _, tt := range []struct {
+		opts   Options
+		option string
+		msg    string
+	}{
+		{Options{Type: "int"}, "tree", "node redeclared"},
+		{Options{Type: "int", Tree: "IntTree", Less: "%s.Less(%s)"},
+			"less", "k.Less undefined"},
+		{Options{Type: "T", Tree: "TTree", Comparable: true},
+			"comparable", "operator < not defined"},
+		{Options{Type: "int", Tree: "IntTree", Tests: true,
+			TestKey: "key(%s)"}, "test-key", "undefined: key"},
+		{Options{Type: "Nope", Tree: "NopeTree", Less: "%s < %s",
+			Equal: "%s == %s"}, "type", "undefined: Nope"},
+		{Options{Type: "int", Tree: "IntTree", Tests: true,
+			Templates: tpl}, "templates", `block "extraTests"`},
+	} {
+		tt.opts.Structure, tt.opts.Package, tt.opts.Output = "rbtree", "mypkg",
+			output
+		var _, err = Generate(tt.opts)
+		var oe *OptionError
+		if !errors.As(err, &oe) {
+			t.Errorf("%s: want *OptionError, got %v", tt.option, err)
+			continue
+		}
+		if oe.Option != tt.option || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("wrong error %q, want -%s: %s", err, tt.option, tt.msg)
+		}
+	}
+	// valid tree
+	var _, err = Generate(Options{Structure: "avltree", Type: "T",
+		Tree: "TTree", Less: "%s.a < %s.a", Equal: "%s == %s",
+		Package: "mypkg", Output: output})
+	if err != nil {
+		t.Error(err)
+	}
+}
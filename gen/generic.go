@@ -0,0 +1,288 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"errors"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"text/template"
+)
+
+// check options of a generic tree and fill the type
+// parameters K and V, and comparison of ordered keys;
+// tests instantiate the tree with int keys and string
+// values
+func (r *rbTree) generic() (err error) {
+	switch {
+	case r.Type != "" || r.Value != "":
+		return optionErr("generic", errors.New("type parameters K and V "+
+			"replace the -type and the -value"))
+	case r.Less != "" || r.Equal != "" || r.Comparable:
+		return optionErr("generic", errors.New("constructors of the tree "+
+			"replace the -less, the -equal and the -comparable"))
+	}
+	r.Type, r.Value = "K", "V"
+	r.Less, r.Equal = "%s < %s", "%s == %s"
+	r.keyType, r.valueType = types.Typ[types.Int], types.Typ[types.String]
+	return
+}
+
+// render the NewFunc variant of a generic tree, that
+// compares keys using the compare function of the tree
+func (r *rbTree) renderFunc(tmpl *template.Template) (src []byte, err error) {
+	var less, equal = r.Less, r.Equal
+	r.Func, r.Less, r.Equal = true, "t.compare(%s, %s) < 0",
+		"t.compare(%s, %s) == 0"
+	defer func() { r.Func, r.Less, r.Equal = false, less, equal }()
+	return render(tmpl, r.Structure, r)
+}
+
+// name of given declaration, a method is 'Type.Method'
+func declName(decl ast.Decl) (name string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil {
+			return d.Name.Name
+		}
+		var typ = d.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if id, ok := typ.(*ast.Ident); ok {
+			name = id.Name
+		}
+		return name + "." + d.Name.Name
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				return s.Name.Name
+			case *ast.ValueSpec:
+				return s.Names[0].Name
+			}
+		}
+	}
+	return // import
+}
+
+// merge given sources of a generic tree and its Func variant,
+// the variant shares node, bounds and other declarations of
+// the tree and adds its type, methods and the constructor
+func mergeFunc(src, fsrc []byte) (_ []byte, err error) {
+	var (
+		fset        = token.NewFileSet()
+		file, ffile *ast.File
+		declared    = make(map[string]bool)
+		merged      = append([]byte(nil), src...)
+	)
+	if file, err = parser.ParseFile(fset, "", src, parser.ParseComments); err != nil {
+		return
+	}
+	if ffile, err = parser.ParseFile(fset, "", fsrc, parser.ParseComments); err != nil {
+		return
+	}
+	for _, decl := range file.Decls {
+		declared[declName(decl)] = true
+	}
+	for _, decl := range ffile.Decls {
+		var name = declName(decl)
+		if name == "" || declared[name] {
+			continue
+		}
+		var start = decl.Pos()
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		}
+		merged = append(merged, '\n')
+		merged = append(merged,
+			fsrc[fset.Position(start).Offset:fset.Position(decl.End()).Offset]...)
+		merged = append(merged, '\n')
+	}
+	return format.Source(merged)
+}
+
+// a generics is generic types and functions of a generated tree
+type generics struct {
+	types map[string]bool // generic types, like the node
+	funcs map[string]bool // functions requiring type arguments, like the New
+}
+
+// genericize turns given source of a tree of K keys and V values
+// to generic one: types and functions using the K or the V get
+// type parameters and references to them get type arguments
+func genericize(src []byte) (_ []byte, g *generics, err error) {
+	var (
+		fset = token.NewFileSet()
+		file *ast.File
+	)
+	if file, err = parser.ParseFile(fset, "", src, parser.ParseComments); err != nil {
+		return
+	}
+	g = &generics{types: make(map[string]bool), funcs: make(map[string]bool)}
+
+	var specs []*ast.TypeSpec
+	for _, decl := range file.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+			for _, spec := range gd.Specs {
+				specs = append(specs, spec.(*ast.TypeSpec))
+			}
+		}
+	}
+	// a type using a generic type is generic too
+	for changed := true; changed; {
+		changed = false
+		for _, spec := range specs {
+			if !g.types[spec.Name.Name] && g.uses(spec.Type) {
+				g.types[spec.Name.Name], changed = true, true
+			}
+		}
+	}
+
+	var ins = make(map[token.Pos]string) // text to insert
+	for _, spec := range specs {
+		if g.types[spec.Name.Name] && spec.TypeParams == nil {
+			ins[spec.Name.End()] = "[K, V any]"
+		}
+	}
+	for _, decl := range file.Decls {
+		var fd, ok = decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil {
+			continue // methods use type parameters of receivers
+		}
+		if fd.Type.TypeParams == nil {
+			if !g.uses(fd) {
+				continue // not generic
+			}
+			ins[fd.Name.End()] = "[K, V any]"
+		}
+		// can't infer type arguments from arguments
+		g.funcs[fd.Name.Name] = !g.uses(fd.Type.Params)
+	}
+
+	g.arguments(file, ins)
+	if src, err = insert(fset, src, ins); err != nil {
+		return
+	}
+	return src, g, nil
+}
+
+// instantiate generic types and functions of the g in given
+// source of tests, the tests declare K and V types
+func (g *generics) instantiate(src []byte) (_ []byte, err error) {
+	var (
+		fset = token.NewFileSet()
+		file *ast.File
+	)
+	if file, err = parser.ParseFile(fset, "", src, parser.ParseComments); err != nil {
+		return
+	}
+	var ins = make(map[token.Pos]string)
+	g.arguments(file, ins)
+	return insert(fset, src, ins)
+}
+
+// is given node uses the K, the V or a generic type
+func (g *generics) uses(node ast.Node) (ok bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, is := n.(*ast.SelectorExpr); is {
+			ast.Inspect(sel.X, func(n ast.Node) bool {
+				ok = ok || g.isGeneric(n)
+				return !ok
+			})
+			return false // skip the selector
+		}
+		ok = ok || g.isGeneric(n)
+		return !ok
+	})
+	return
+}
+
+func (g *generics) isGeneric(n ast.Node) bool {
+	var id, ok = n.(*ast.Ident)
+	return ok && (id.Name == "K" || id.Name == "V" || g.types[id.Name])
+}
+
+// add type arguments to references of generic types and
+// functions of the g
+func (g *generics) arguments(file *ast.File, ins map[token.Pos]string) {
+	var skip = make(map[*ast.Ident]bool) // not references
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.SelectorExpr:
+			skip[x.Sel] = true
+		case *ast.IndexExpr:
+			if id, ok := x.X.(*ast.Ident); ok {
+				skip[id] = true // already has arguments
+			}
+		case *ast.IndexListExpr:
+			if id, ok := x.X.(*ast.Ident); ok {
+				skip[id] = true
+			}
+		case *ast.TypeSpec:
+			skip[x.Name] = true
+		case *ast.FuncDecl:
+			skip[x.Name] = true
+		case *ast.KeyValueExpr:
+			if id, ok := x.Key.(*ast.Ident); ok {
+				skip[id] = true // field name
+			}
+		case *ast.Ident:
+			if !skip[x] && (g.types[x.Name] || g.funcs[x.Name]) {
+				ins[x.End()] = "[K, V]"
+			}
+		}
+		return true
+	})
+}
+
+// insert texts to given source and format it
+func insert(fset *token.FileSet, src []byte,
+	ins map[token.Pos]string) ([]byte, error) {
+
+	var pos = make([]token.Pos, 0, len(ins))
+	for p := range ins {
+		pos = append(pos, p)
+	}
+	sort.Slice(pos, func(i, j int) bool { return pos[i] > pos[j] })
+	var res = append([]byte(nil), src...)
+	for _, p := range pos {
+		var off = fset.Position(p).Offset
+		res = append(res[:off], append([]byte(ins[p]), res[off:]...)...)
+	}
+	return format.Source(res)
+}
@@ -0,0 +1,213 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+var update = flag.Bool("update", false, "update golden files of the TestGolden")
+
+// generated trees of the TestGolden; a golden file of a tree
+// is testdata/golden/<name>.golden, and <name>_test.golden
+// is golden file of its tests
+var goldenTrees = []struct {
+	name string
+	opts Options
+}{
+	{"rbtree", Options{Structure: "rbtree", Type: "int", Value: "string"}},
+	{"rbtree-set", Options{Structure: "rbtree", Type: "int"}},
+	{"rbtree-stacked", Options{Structure: "rbtree", Type: "int",
+		Value: "string", Stacked: true}},
+	{"rbtree-unique", Options{Structure: "rbtree", Type: "int",
+		Value: "string", Unique: true}},
+	{"rbtree-thread-safe", Options{Structure: "rbtree", Type: "int",
+		Value: "string", ThreadSafe: true}},
+	{"rbtree-print", Options{Structure: "rbtree", Type: "int",
+		Value: "string", Printer: true}},
+	{"rbtree-generic", Options{Structure: "rbtree", Generic: true}},
+	{"rbtree-ll", Options{Structure: "rbtree", Type: "int",
+		Value: "string", LeftLeaning: true}},
+	{"rbtree-ll-set-unique", Options{Structure: "rbtree", Type: "int",
+		LeftLeaning: true, Unique: true}},
+	{"rbtree-counted", Options{Structure: "rbtree", Type: "int",
+		Value: "string", Counted: true}},
+	{"rbtree-ll-counted", Options{Structure: "rbtree", Type: "int",
+		Value: "string", LeftLeaning: true, Counted: true}},
+	{"rbtree-tests", Options{Structure: "rbtree", Type: "int",
+		Value: "string", Tree: "IntTree", Tests: true}},
+	{"avltree", Options{Structure: "avltree", Type: "int", Value: "string"}},
+	{"avltree-set", Options{Structure: "avltree", Type: "int"}},
+	{"avltree-stacked-unique", Options{Structure: "avltree", Type: "int",
+		Stacked: true, Unique: true}},
+	{"avltree-thread-safe-print", Options{Structure: "avltree", Type: "int",
+		Value: "string", ThreadSafe: true, Printer: true}},
+	{"avltree-stacked-counted", Options{Structure: "avltree", Type: "int",
+		Stacked: true, Counted: true}},
+	{"avltree-generic", Options{Structure: "avltree", Generic: true,
+		Stacked: true}},
+}
+
+// trees of the variants test generated to one package, the
+// test compares trees with parent pointers and stacked trees
+var variantTrees = []Options{
+	{Structure: "rbtree", Tree: "RBTree"},
+	{Structure: "rbtree", Tree: "RBStackedTree", Stacked: true},
+	{Structure: "avltree", Tree: "AVLTree"},
+	{Structure: "avltree", Tree: "AVLStackedTree", Stacked: true},
+}
+
+// compare generated file with its golden file or update the golden
+func checkGolden(t *testing.T, golden string, src []byte) {
+	t.Helper()
+	if *update {
+		writeFile(t, golden, src)
+		return
+	}
+	var want, err = ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(src, want) {
+		return
+	}
+	var got, exp = strings.Split(string(src), "\n"), strings.Split(string(want), "\n")
+	var line int
+	for line < len(got) && line < len(exp) && got[line] == exp[line] {
+		line++
+	}
+	var g, e string
+	if line < len(got) {
+		g = got[line]
+	}
+	if line < len(exp) {
+		e = exp[line]
+	}
+	t.Errorf("%s:%d: got\n\t%s\nwant\n\t%s\nuse -update if the change is expected",
+		golden, line+1, g, e)
+}
+
+// write file creating its directory
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read file or fail
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+	var data, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// The TestGolden generates the trees to a temporary module, compares
+// them with golden files, and runs the shared behaviour test against
+// every tree and the variants test; use -update to update the golden
+// files and -short to skip the behaviour and the variants tests
+func TestGolden(t *testing.T) {
+	var (
+		dir       = t.TempDir()
+		behaviour = readFile(t, filepath.Join("testdata", "behaviour_test.go"))
+		adapter   = template.Must(template.ParseFiles(
+			filepath.Join("testdata", "adapter.tmpl")))
+	)
+	writeFile(t, filepath.Join(dir, "go.mod"),
+		[]byte("module github.com/logrusorgru/gods\n\ngo 1.21\n"))
+
+	for _, gt := range goldenTrees {
+		var opts = gt.opts
+		opts.Package = "p"
+		opts.Output = filepath.Join(dir, gt.name, "tree.go")
+		if err := os.MkdirAll(filepath.Dir(opts.Output), 0755); err != nil {
+			t.Fatal(err)
+		}
+		var fs, err = GenerateFiles(opts)
+		if err != nil {
+			t.Errorf("%s: %v", gt.name, err)
+			continue
+		}
+		for _, f := range fs {
+			var suffix = strings.TrimPrefix(filepath.Base(f.Path), "tree")
+			var golden = gt.name + strings.TrimSuffix(suffix, ".go") + ".golden"
+			checkGolden(t, filepath.Join("testdata", "golden", golden), f.Src)
+			writeFile(t, f.Path, f.Src)
+		}
+		var r = &rbTree{Options: opts}
+		if r.Tree == "" {
+			r.Tree = "Tree"
+		}
+		r.KeyValue = r.Value != ""
+		var buf bytes.Buffer
+		if err = adapter.Execute(&buf, r); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, gt.name, "adapter_test.go"), buf.Bytes())
+		writeFile(t, filepath.Join(dir, gt.name, "behaviour_test.go"), behaviour)
+	}
+
+	for _, opts := range variantTrees {
+		opts.Type, opts.Value, opts.Package = "int", "string", "variants"
+		opts.Output = filepath.Join(dir, "variants",
+			strings.ToLower(opts.Tree)+".go")
+		var src, err = Generate(opts)
+		if err != nil {
+			t.Fatalf("%s: %v", opts.Tree, err)
+		}
+		writeFile(t, opts.Output, src)
+	}
+	writeFile(t, filepath.Join(dir, "variants", "variants_test.go"),
+		readFile(t, filepath.Join("testdata", "variants_test.go")))
+
+	if testing.Short() {
+		t.Skip("behaviour test skipped in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("behaviour test requires go command")
+	}
+	var cmd = exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off",
+		"GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("behaviour test: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+)
+
+// first lines of a generated file, the second line is
+// the command reproducing the file
+const (
+	generatedPrefix = "// Code generated by gods "
+	commandPrefix   = "// gods "
+)
+
+// command line of the tree, paths are relative to
+// directory of the output; it should be called
+// before the validate that fills computed fields
+func (r *rbTree) commandLine() string {
+	var (
+		c   Options
+		set = flag.NewFlagSet(r.Structure, flag.ContinueOnError)
+	)
+	c.Flags(set)
+	c = r.Options // the flags point to fields of the c
+	if c.Output != "" {
+		var dir = filepath.Dir(c.Output)
+		if c.Templates != "" {
+			if rel, err := filepath.Rel(dir, c.Templates); err == nil {
+				c.Templates = rel
+			}
+		}
+		c.Output = filepath.Base(c.Output)
+	}
+	var args = []string{"gods", r.Structure}
+	set.VisitAll(func(f *flag.Flag) {
+		switch {
+		case f.Name == "import":
+			for _, path := range c.Imports {
+				args = append(args, "-import", quoteArg(path))
+			}
+		case f.Value.String() == f.DefValue:
+		case f.DefValue == "false": // boolean
+			args = append(args, "-"+f.Name)
+		default:
+			args = append(args, "-"+f.Name, quoteArg(f.Value.String()))
+		}
+	})
+	return strings.Join(args, " ")
+}
+
+// quote given argument for the SplitArgs if necessary
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t'\"\\") {
+		return arg
+	}
+	return "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+}
+
+// Command returns command line arguments reproducing given
+// generated file, the first argument is name of the structure
+func Command(src []byte) (args []string, err error) {
+	var (
+		sc    = bufio.NewScanner(bytes.NewReader(src))
+		lines []string
+	)
+	for len(lines) < 2 && sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], generatedPrefix) ||
+		!strings.HasPrefix(lines[1], commandPrefix) {
+		return nil, errors.New("not generated by gods or generated by " +
+			"an old version")
+	}
+	if args, err = SplitArgs(strings.TrimPrefix(lines[1], commandPrefix)); err != nil {
+		return
+	}
+	if len(args) == 0 {
+		return nil, errors.New("missing name of structure")
+	}
+	return
+}
+
+// SplitArgs splits given line to arguments like a shell does; it
+// supports single and double quotes and backslash escaping
+func SplitArgs(line string) (args []string, err error) {
+	var (
+		arg    []rune
+		inArg  bool // there is an argument, even empty ''
+		quote  rune // current quote or zero
+		escape bool // previous rune is backslash
+	)
+	for _, r := range line {
+		switch {
+		case escape:
+			arg, escape = append(arg, r), false
+		case r == '\\' && quote != '\'':
+			escape, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg = append(arg, r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args, arg, inArg = append(args, string(arg)), arg[:0], false
+			}
+		default:
+			arg, inArg = append(arg, r), true
+		}
+	}
+	if quote != 0 || escape {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, string(arg))
+	}
+	return
+}
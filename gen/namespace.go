@@ -0,0 +1,216 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// a namespace renames package-level identifiers of generated files
+// of a tree, thus many trees can be generated to one package; the
+// 'node' of the IntTree becomes 'intTreeNode', and the 'WalkFunc'
+// becomes 'IntTreeWalkFunc'
+type namespace struct {
+	prefix string            // the tree type
+	keep   map[string]bool   // the tree type and its constructors
+	names  map[string]string // renamed identifiers
+}
+
+// namespace of the tree, it's nil for default Tree, that
+// keeps package-level names as is, like the New does
+func (r *rbTree) namespace() (ns *namespace) {
+	if r.TreeType() == "Tree" {
+		return
+	}
+	ns = new(namespace)
+	ns.prefix = r.TreeType()
+	ns.keep = map[string]bool{
+		r.TreeType():          true,
+		r.New():               true,
+		r.TreeType() + "Func": true, // generic
+		r.New() + "Func":      true,
+	}
+	ns.names = make(map[string]string)
+	return
+}
+
+// name of given identifier in the namespace
+func (ns *namespace) name(id string) string {
+	var r, _ = utf8.DecodeRuneInString(id)
+	if unicode.IsUpper(r) {
+		return ns.prefix + id
+	}
+	var p, size = utf8.DecodeRuneInString(ns.prefix)
+	return string(unicode.ToLower(p)) + ns.prefix[size:] +
+		strings.ToUpper(id[:1]) + id[1:]
+}
+
+// is given function a test, a benchmark or an example
+func isTestFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// rename package-level identifiers declared by given source
+// and identifiers of previous sources of the namespace
+func (ns *namespace) rename(src []byte) (_ []byte, err error) {
+	var (
+		fset = token.NewFileSet()
+		file *ast.File
+	)
+	if file, err = parser.ParseFile(fset, "", src, parser.ParseComments); err != nil {
+		return
+	}
+
+	var (
+		decls = make(map[interface{}]bool) // package-level declarations
+		reps  []replacement
+	)
+	var declare = func(id *ast.Ident, decl interface{}, doc *ast.CommentGroup) {
+		decls[decl] = true
+		if id.Name == "_" || id.Name == "init" || ns.keep[id.Name] {
+			return
+		}
+		ns.names[id.Name] = ns.name(id.Name)
+		if doc != nil {
+			reps = append(reps, words(fset, doc, id.Name, ns.names[id.Name])...)
+		}
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && !isTestFunc(d.Name.Name) {
+				declare(d.Name, d, d.Doc)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					var doc = s.Doc
+					if doc == nil && len(d.Specs) == 1 {
+						doc = d.Doc
+					}
+					declare(s.Name, s, doc)
+				case *ast.ValueSpec:
+					var doc = s.Doc
+					if doc == nil && len(d.Specs) == 1 {
+						doc = d.Doc
+					}
+					for _, id := range s.Names {
+						declare(id, s, doc)
+					}
+				}
+			}
+		}
+	}
+
+	var skip = make(map[*ast.Ident]bool) // not references
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.SelectorExpr:
+			skip[x.Sel] = true
+		case *ast.FuncDecl:
+			if x.Recv != nil {
+				skip[x.Name] = true // method
+			}
+		case *ast.StructType:
+			for _, f := range x.Fields.List {
+				for _, id := range f.Names {
+					skip[id] = true
+				}
+			}
+		case *ast.KeyValueExpr:
+			if id, ok := x.Key.(*ast.Ident); ok {
+				skip[id] = true // field name
+			}
+		case *ast.Ident:
+			var name, ok = ns.names[x.Name]
+			if !ok || skip[x] {
+				break
+			}
+			// unresolved identifiers are declared by other files
+			if x.Obj == nil || decls[x.Obj.Decl] {
+				reps = append(reps, replacement{
+					fset.Position(x.Pos()).Offset,
+					fset.Position(x.End()).Offset,
+					name,
+				})
+			}
+		}
+		return true
+	})
+
+	sort.Slice(reps, func(i, j int) bool {
+		return reps[i].start > reps[j].start
+	})
+	var res = append([]byte(nil), src...)
+	for _, e := range reps {
+		res = append(res[:e.start], append([]byte(e.text), res[e.end:]...)...)
+	}
+	return format.Source(res)
+}
+
+// a replacement replaces [start, end) of a source with the text
+type replacement struct {
+	start, end int
+	text       string
+}
+
+// words replaces whole words of given comment
+func words(fset *token.FileSet, doc *ast.CommentGroup, word,
+	text string) (reps []replacement) {
+
+	for _, c := range doc.List {
+		var offset = fset.Position(c.Pos()).Offset
+		for i := 0; i+len(word) <= len(c.Text); i++ {
+			if c.Text[i:i+len(word)] != word ||
+				i > 0 && isIdentByte(c.Text[i-1]) ||
+				i+len(word) < len(c.Text) && isIdentByte(c.Text[i+len(word)]) {
+				continue
+			}
+			reps = append(reps, replacement{offset + i, offset + i + len(word), text})
+			i += len(word) - 1
+		}
+	}
+	return
+}
+
+// is given byte a part of an identifier
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' ||
+		b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
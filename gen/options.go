@@ -0,0 +1,236 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"flag"
+	"path/filepath"
+)
+
+// Options of a generated structure. The fields are named after
+// flags of the structure in a configuration file, except the
+// Output that is the 'o' flag. Use the Flags to set defaults.
+type Options struct {
+	Structure   string  `json:"kind"`                  // rbtree, avltree
+	Stacked     bool    `json:"stacked,omitempty"`     // track parent reference on stack
+	LeftLeaning bool    `json:"ll,omitempty"`          // left-leaning red-black tree
+	Generic     bool    `json:"generic,omitempty"`     // Tree[K, V any]
+	Unique      bool    `json:"unique,omitempty"`      // unique (single value per node)
+	Counted     bool    `json:"counted,omitempty"`     // keep sizes of subtrees
+	ThreadSafe  bool    `json:"thread-safe,omitempty"` // thread safe tree
+	Type        string  `json:"type"`                  // type of item
+	Value       string  `json:"value,omitempty"`       // type of value
+	Comparable  bool    `json:"comparable,omitempty"`  // type is comparable
+	Less        string  `json:"less,omitempty"`        // less format
+	Equal       string  `json:"equal,omitempty"`       // equal format
+	Prefix      string  `json:"prefix,omitempty"`      // name space prefix
+	Imports     Strings `json:"import,omitempty"`      // add imports
+	Tree        string  `json:"tree,omitempty"`        // tree type name
+	Printer     bool    `json:"print,omitempty"`       // add Print and String methods
+	Package     string  `json:"package"`               // package name
+	Output      string  `json:"output"`                // output file name
+	Tests       bool    `json:"tests,omitempty"`       // generate tests
+	TestKey     string  `json:"test-key,omitempty"`    // int to key format
+	TestValue   string  `json:"test-value,omitempty"`  // int to value format
+	Templates   string  `json:"templates,omitempty"`   // user templates
+}
+
+// Flags registers flags of all the options in given set and
+// sets default values of the options; use flags of a Generator
+// to get only options supported by its structure
+func (o *Options) Flags(set *flag.FlagSet) {
+	set.BoolVar(&o.Stacked,
+		"stacked",
+		false,
+		"track parent references on stack")
+	set.BoolVar(&o.LeftLeaning,
+		"ll",
+		false,
+		"left-leaning Red-black tree")
+	set.BoolVar(&o.Generic,
+		"generic",
+		false,
+		"generic Tree[K, V any] instead of the -type and the -value")
+	set.BoolVar(&o.Unique,
+		"unique",
+		false,
+		"don't allow many values per node")
+	set.BoolVar(&o.Counted,
+		"counted",
+		false,
+		"keep sizes of subtrees for Rank, Select and CountRange")
+	set.BoolVar(&o.ThreadSafe,
+		"thread-safe",
+		false,
+		"thread-safe tree")
+	set.StringVar(&o.Type,
+		"type",
+		"",
+		"type of item or key, like 'int', 'Name' of the package,\n"+
+			"'time.Time' or '*github.com/user/pkg.Name'")
+	set.StringVar(&o.Value,
+		"value",
+		"",
+		"type of value, produce tree with key-value pairs")
+	set.BoolVar(&o.Comparable,
+		"comparable",
+		false,
+		"the type is comparable using '<' and '==' operators")
+	set.StringVar(&o.Less,
+		"less",
+		"",
+		"format of less comparison, like '%s.Less(%s)' or 'less(%s, %s)', etc;\n"+
+			"detected by the type if omitted")
+	set.StringVar(&o.Equal,
+		"equal",
+		"",
+		"format of equal comparison, like '%s.Eq(%s)' or 'equal(%s, %s)', etc;\n"+
+			"detected by the type if omitted")
+	set.BoolVar(&o.Printer,
+		"print",
+		false,
+		"add Print and String methods rendering the tree")
+	set.Var(&o.Imports,
+		"import",
+		"import package (reuse flag for list of packages)")
+	set.StringVar(&o.Prefix,
+		"prefix",
+		"",
+		"name space prefix")
+	set.StringVar(&o.Tree,
+		"tree",
+		"Tree",
+		"tree type name")
+	set.StringVar(&o.Package,
+		"package",
+		"",
+		"package name")
+	set.StringVar(&o.Output,
+		"o",
+		"",
+		"output file name")
+	set.BoolVar(&o.Tests,
+		"tests",
+		false,
+		"generate tests and benchmarks to _test.go file next to the output")
+	set.StringVar(&o.TestKey,
+		"test-key",
+		"",
+		"format converting int to key for tests, the conversion must keep\n"+
+			"order; like 'pkg.NewKey(%s)'; numbers and strings are converted\n"+
+			"automatically; use the -import for required packages")
+	set.StringVar(&o.TestValue,
+		"test-value",
+		"",
+		"format converting int to value for tests, like 'strconv.Itoa(%s)';\n"+
+			"numbers and strings are converted automatically")
+	set.StringVar(&o.Templates,
+		"templates",
+		"",
+		"directory with templates overriding blocks of built-in ones,\n"+
+			"use 'gods templates dump' to get the built-in templates")
+}
+
+// register flags of the options except given ones
+func (o *Options) flags(set *flag.FlagSet, skip ...string) {
+	var all = flag.NewFlagSet(set.Name(), flag.ContinueOnError)
+	o.Flags(all)
+	all.VisitAll(func(f *flag.Flag) {
+		if !Strings(skip).Contain(f.Name) {
+			set.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+}
+
+// RelativeTo makes relative output and templates
+// paths relative to given directory
+func (o *Options) RelativeTo(dir string) {
+	for _, path := range []*string{&o.Output, &o.Templates} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+}
+
+// An OptionError is an error of an option. The Option
+// is name of the field in a configuration file.
+type OptionError struct {
+	Option string
+	Err    error
+}
+
+func optionErr(option string, err error) error {
+	return &OptionError{Option: option, Err: err}
+}
+
+// Flag is name of flag of the option
+func (o *OptionError) Flag() string {
+	if o.Option == "output" {
+		return "o"
+	}
+	return o.Option
+}
+
+// Error implements error interface
+func (o *OptionError) Error() string {
+	return "-" + o.Flag() + ": " + o.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (o *OptionError) Unwrap() error {
+	return o.Err
+}
+
+// Strings represents list of strings
+type Strings []string
+
+// String implements flag.Value interface
+func (s Strings) String() (ss string) {
+	for _, x := range s {
+		ss += x + ","
+	}
+	if len(ss) > 0 {
+		ss = ss[:len(ss)-1] // trim trailing comma
+	}
+	return
+}
+
+// Set implements flag.Value interface
+func (s *Strings) Set(val string) (_ error) {
+	*s = append(*s, val)
+	return
+}
+
+// Contain returns true if the
+// Strings contain given element
+func (s Strings) Contain(name string) bool {
+	for _, x := range s {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1155 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"text/template"
+)
+
+// an rbTree is data of templates of a tree
+type rbTree struct {
+	Options
+
+	KeyValue bool // key value pairs
+	Func     bool // the NewFunc variant of a generic tree
+
+	given     Options       // options before validation
+	kind      string        // kind of the tree, like "red-black" or "AVL"
+	command   string        // command line reproducing the tree
+	keyType   types.Type    // resolved type of key, can be nil
+	valueType types.Type    // resolved type of value, can be nil
+	tr        *typeResolver // types of the target package
+}
+
+// check options and fill computed fields
+func (r *rbTree) validate() (err error) {
+	if r.Generic {
+		if err = r.generic(); err != nil {
+			return
+		}
+	}
+	switch {
+	case r.Type == "":
+		return optionErr("type", errors.New("missing type of item or key"))
+	case r.Package == "":
+		return optionErr("package", errors.New("missing package name"))
+	case !token.IsIdentifier(r.Package):
+		return optionErr("package", fmt.Errorf("invalid package name %q",
+			r.Package))
+	case !token.IsIdentifier(r.Tree):
+		return optionErr("tree", fmt.Errorf("invalid tree name %q", r.Tree))
+	case r.Prefix != "" && !token.IsIdentifier(r.Prefix):
+		return optionErr("prefix", fmt.Errorf("invalid prefix %q", r.Prefix))
+	case r.LeftLeaning && r.kind != "red-black":
+		return optionErr("ll", fmt.Errorf("not supported by %s tree", r.kind))
+	case r.Comparable && (r.Less != "" || r.Equal != ""):
+		return optionErr("comparable", errors.New("operators '<' and '==' "+
+			"conflict with the -less and the -equal"))
+	}
+	if r.Comparable {
+		r.Less, r.Equal = "%s < %s", "%s == %s"
+	}
+	if r.LeftLeaning {
+		r.Stacked = true // no parent references
+	}
+	if err = r.resolve(); err != nil {
+		return
+	}
+	r.KeyValue = r.Value != ""
+	return r.validateTests()
+}
+
+// Kind of the tree
+func (r *rbTree) Kind() string {
+	return r.kind
+}
+
+// Version of the generator
+func (r *rbTree) Version() string {
+	return Version
+}
+
+// Command line reproducing the tree
+func (r *rbTree) Command() string {
+	return r.command
+}
+
+// TreeType is name of the tree type
+func (r *rbTree) TreeType() string {
+	if r.Func {
+		return r.Prefix + r.Tree + "Func"
+	}
+	return r.Prefix + r.Tree
+}
+
+// New is name of the tree constructor
+func (r *rbTree) New() string {
+	if tt := r.Prefix + r.Tree; tt != "Tree" {
+		return "New" + tt
+	}
+	return "New"
+}
+
+// ImportList returns sorted list of imports
+// required by the generated tree
+func (r *rbTree) ImportList() (list []string) {
+	var seen = make(map[string]bool)
+	var add = func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			list = append(list, path)
+		}
+	}
+	for _, path := range r.Imports {
+		add(path)
+	}
+	if r.ThreadSafe {
+		add("sync")
+	}
+	if r.Generic {
+		add("cmp")
+	}
+	if r.Printer {
+		add("fmt")
+		add("strings")
+	}
+	sort.Strings(list)
+	return
+}
+
+// template functions of a tree
+func (r *rbTree) funcs() template.FuncMap {
+	// the value is key itself if the tree is not a key-value
+	var vtype, arg, field = r.Type, "k", ".k"
+	if r.KeyValue {
+		vtype, arg, field = r.Value, "v", ".v"
+	}
+	return template.FuncMap{
+		// comparison
+		"less": func(a, b string) string {
+			return fmt.Sprintf(r.Less, a, b)
+		},
+		"equal": func(a, b string) string {
+			return fmt.Sprintf(r.Equal, a, b)
+		},
+		// comparison in free functions of tests
+		"testLess": func(a, b string) string {
+			if r.Generic {
+				return a + " < " + b
+			}
+			return fmt.Sprintf(r.Less, a, b)
+		},
+		"testEqual": func(a, b string) string {
+			if r.Generic {
+				return a + " == " + b
+			}
+			return fmt.Sprintf(r.Equal, a, b)
+		},
+		// 'k K, v V' or 'k K' parameters
+		"params": func() string {
+			if r.KeyValue {
+				return "k " + r.Type + ", v " + r.Value
+			}
+			return "k " + r.Type
+		},
+		// 'k, v' or 'k' arguments
+		"args": func() string {
+			if r.KeyValue {
+				return "k, v"
+			}
+			return "k"
+		},
+		// type of value
+		"vtype": func() string { return vtype },
+		// type of bounds of ranges, 'Bound' or 'Bound[K]'
+		"bound": func() string {
+			if r.Generic {
+				return "Bound[K]"
+			}
+			return "Bound"
+		},
+		// the value argument
+		"arg": func() string { return arg },
+		// value field of given node
+		"val": func(n string) string { return n + field },
+		// path to a node, the dad or ancestors (stacked),
+		// a left-leaning tree doesn't need the path
+		"path": func() string {
+			if r.LeftLeaning {
+				return "_"
+			}
+			if r.Stacked {
+				return "st"
+			}
+			return "d"
+		},
+		// 'n.k, n.v' or 'n.k' arguments of given node
+		"pair": func(n string) string {
+			if r.KeyValue {
+				return n + ".k, " + n + ".v"
+			}
+			return n + ".k"
+		},
+		// tests, 'testKey(k), testValue(v)' or 'testKey(k)' arguments
+		"testArgs": func(k, v string) string {
+			if r.KeyValue {
+				return "testKey(" + k + "), testValue(" + v + ")"
+			}
+			return "testKey(" + k + ")"
+		},
+		// tests, 'testValue(v)' or 'testKey(k)' expected value
+		"testVal": func(k, v string) string {
+			if r.KeyValue {
+				return "testValue(" + v + ")"
+			}
+			return "testKey(" + k + ")"
+		},
+		// tests, conversion of int i to key or value
+		"testKeyExpr": func(i string) string {
+			return fmt.Sprintf(r.TestKey, i)
+		},
+		"testValueExpr": func(i string) string {
+			return fmt.Sprintf(r.TestValue, i)
+		},
+	}
+}
+
+const rbTreeTemplate = `{{ template "header" . }}
+
+{{ template "node" . }}
+
+{{ template "zero" . }}
+
+{{ template "bound" . }}
+
+{{ template "tree" . }}
+
+{{ template "find" . }}
+
+{{ template "balance" . }}
+
+{{ template "ins" . }}
+
+{{ template "delete" . }}
+
+{{ template "access" . }}
+
+{{ template "walk" . }}
+
+{{ template "count" . }}
+
+{{ template "print" . }}
+
+{{ template "extra" . }}
+
+{{- define "node" }}
+{{- if .LeftLeaning }}
+type color bool
+
+const (
+	red   color = true
+	black color = false
+)
+
+type node struct {
+	l, r *node
+	c    color
+{{- if .Counted }}
+	s    int // size of the subtree
+{{- end }}
+	k    {{ .Type }}
+{{- if .KeyValue }}
+	v    {{ .Value }}
+{{- end }}
+}
+
+func newNode({{ params }}) (n *node) {
+	n = new(node)
+	n.c = red
+{{- if .Counted }}
+	n.s = 1
+{{- end }}
+	n.k = k
+{{- if .KeyValue }}
+	n.v = v
+{{- end }}
+	return
+}
+
+func (n *node) isRed() bool {
+	return n != nil && n.c == red
+}
+
+// the n becomes right child of its left child
+func (n *node) rotateRight() (x *node) {
+	x = n.l
+	n.l, x.r = x.r, n
+	x.c, n.c = n.c, red
+{{- if .Counted }}
+	n.recount()
+	x.recount()
+{{- end }}
+	return
+}
+
+// the n becomes left child of its right child
+func (n *node) rotateLeft() (x *node) {
+	x = n.r
+	n.r, x.l = x.l, n
+	x.c, n.c = n.c, red
+{{- if .Counted }}
+	n.recount()
+	x.recount()
+{{- end }}
+	return
+}
+
+// flip colors of the n and its children
+func (n *node) flipColors() {
+	n.c = !n.c
+	n.l.c = !n.l.c
+	n.r.c = !n.r.c
+}
+
+// restore left-leaning invariants on the way up
+func (n *node) fixUp() *node {
+	if n.r.isRed() && !n.l.isRed() {
+		n = n.rotateLeft()
+	}
+	if n.l.isRed() && n.l.l.isRed() {
+		n = n.rotateRight()
+	}
+	if n.l.isRed() && n.r.isRed() {
+		n.flipColors()
+	}
+{{- if .Counted }}
+	n.recount()
+{{- end }}
+	return n
+}
+
+// the n is red, make the n.l or one of its children red
+func (n *node) moveRedLeft() *node {
+	n.flipColors()
+	if n.r.l.isRed() {
+		n.r = n.r.rotateRight()
+		n = n.rotateLeft()
+		n.flipColors()
+	}
+	return n
+}
+
+// the n is red, make the n.r or one of its children red
+func (n *node) moveRedRight() *node {
+	n.flipColors()
+	if n.l.l.isRed() {
+		n = n.rotateRight()
+		n.flipColors()
+	}
+	return n
+}
+
+// delete minimal node of the subtree returning
+// new root of the subtree and the deleted node
+func (n *node) deleteMin() (*node, *node) {
+	if n.l == nil {
+		return nil, n
+	}
+	if !n.l.isRed() && !n.l.l.isRed() {
+		n = n.moveRedLeft()
+	}
+	var min *node
+	n.l, min = n.l.deleteMin()
+	return n.fixUp(), min
+}
+{{- else }}
+type color bool
+
+const (
+	red   color = true
+	black color = false
+)
+
+type node struct {
+{{- if .Stacked }}
+	l, r *node
+{{- else }}
+	d, l, r *node
+{{- end }}
+	c       color
+{{- if .Counted }}
+	s       int // size of the subtree
+{{- end }}
+	k       {{ .Type }}
+{{- if .KeyValue }}
+	v       {{ .Value }}
+{{- end }}
+}
+{{ if .Stacked }}
+func newNode({{ params }}) (n *node) {
+	n = new(node)
+{{- else }}
+func newNode(dad *node, {{ params }}) (n *node) {
+	n = new(node)
+	n.d = dad
+{{- end }}
+	n.c = red
+{{- if .Counted }}
+	n.s = 1
+{{- end }}
+	n.k = k
+{{- if .KeyValue }}
+	n.v = v
+{{- end }}
+	return
+}
+
+func (n *node) color() color {
+	if n == nil {
+		return black
+	}
+	return n.c
+}
+
+func (n *node) isBlack() bool {
+	return n.color() == black
+}
+
+func (n *node) isRed() bool {
+	return n.color() == red
+}
+
+{{ if not .Stacked -}}
+func (n *node) left() *node {
+	if n == nil {
+		return nil
+	}
+	return n.l
+}
+
+func (n *node) right() *node {
+	if n == nil {
+		return nil
+	}
+	return n.r
+}
+
+func (n *node) dad() *node {
+	if n == nil {
+		return nil
+	}
+	return n.d
+}
+
+func (n *node) sibling() *node {
+	if left := n.dad().left(); left != n {
+		return left
+	}
+	return n.dad().right()
+}
+
+func (n *node) uncle() *node {
+	return n.dad().sibling()
+}
+
+func (n *node) isLeft() bool {
+	return n.dad().left() == n
+}
+
+func (n *node) isRight() bool {
+	return n.dad().right() == n
+}
+{{ end -}}
+
+func (n *node) setBlack() {
+	if n != nil {
+		n.c = black
+	}
+}
+
+func (n *node) setRed() {
+	if n != nil {
+		n.c = red
+	}
+}
+
+// n becomes red, its children becomes black
+func (n *node) pushBlack() {
+	n.setRed()
+	n.l.setBlack()
+	n.r.setBlack()
+}
+
+{{ if .Stacked -}}
+// left -> right, right, right,...; the ss is the n and
+// nodes between the n and the r
+func (n *node) successor() (ss []*node, r *node) {
+	ss = append(ss, n)
+	if n.l != nil {
+		for r = n.l; r.r != nil; r = r.r {
+			ss = append(ss, r)
+		}
+	} else if n.r != nil {
+		for r = n.r; r.l != nil; r = r.l {
+			ss = append(ss, r)
+		}
+	}
+	return
+}
+
+// other child of the n
+func (n *node) opposite(c *node) *node {
+	if n.l == c {
+		return n.r
+	}
+	return n.l
+}
+{{- else -}}
+// left -> right, right, right,...
+func (n *node) successor() (r *node) {
+	if n.l != nil {
+		for r = n.l; r.r != nil; r = r.r {
+		}
+	} else if n.r != nil {
+		for r = n.r; r.l != nil; r = r.l {
+		}
+	}
+	return
+}
+{{- end }}
+
+func (n *node) replaceChild(old, new *node) {
+	if n.l == old {
+		n.l = new
+	} else {
+		n.r = new
+	}
+}
+
+// node points to at least one black
+func (n *node) hasRedChild() bool {
+	return n != nil && (n.l.isRed() || n.r.isRed())
+}
+
+func (n *node) copy(x *node) {
+	n.k = x.k
+{{- if .KeyValue }}
+	n.v = x.v
+{{- end }}
+}
+{{- end }}
+{{ if .Printer }}
+// label of the n for the Print, like "[R] 5"
+func (n *node) label() string {
+	if n.c == red {
+		return fmt.Sprintf("[R] %v", n.k)
+	}
+	return fmt.Sprintf("[B] %v", n.k)
+}
+{{ end }}
+{{- end }}
+
+{{- define "balance" }}
+{{- if .LeftLeaning }}
+// insert given node to subtree of the h returning
+// new root of the subtree
+func (t *{{ .TreeType }}) insert(h, n *node) *node {
+	if h == nil {
+		return n
+	}
+	if {{ less "n.k" "h.k" }} {
+		h.l = t.insert(h.l, n) // left (less)
+	} else {
+		h.r = t.insert(h.r, n) // right (greater or equal)
+	}
+	return h.fixUp()
+}
+
+// insert node to the tree
+func (t *{{ .TreeType }}) insertNode(n *node) {
+	t.size++
+	t.r = t.insert(t.r, n)
+	t.r.c = black // root must be black
+}
+{{- else }}
+func (t *{{ .TreeType }}) isRoot(n *node) bool {
+	return t.r == n
+}
+{{ if .Stacked }}
+// the st is ancestors of the n
+func (t *{{ .TreeType }}) rightRotate(st []*node, n *node) {
+	var pivot = n.l
+	if _, d := pop(st); d == nil {
+		t.r = pivot
+		pivot.c = black
+	} else {
+		d.replaceChild(n, pivot)
+	}
+	n.l = pivot.r
+	pivot.r = n
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+}
+
+// the st is ancestors of the n
+func (t *{{ .TreeType }}) leftRotate(st []*node, n *node) {
+	var pivot = n.r
+	if _, d := pop(st); d == nil {
+		t.r = pivot
+		pivot.c = black
+	} else {
+		d.replaceChild(n, pivot)
+	}
+	n.r = pivot.l
+	pivot.l = n
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+}
+
+func (t *{{ .TreeType }}) insertLeftLeftBalancing(st []*node, g, d *node) {
+	d.c, g.c = g.c, d.c // swap colors
+	t.rightRotate(st, g)
+}
+
+func (t *{{ .TreeType }}) insertLeftRightBalancing(st []*node, g, d, n *node) {
+	t.leftRotate(append(st, g), d)
+	// the n becomes d after the leftRotate(d)
+	t.insertLeftLeftBalancing(st, g, n)
+}
+
+func (t *{{ .TreeType }}) insertRightRightBalancing(st []*node, g, d *node) {
+	d.c, g.c = g.c, d.c // swap colors
+	t.leftRotate(st, g)
+}
+
+func (t *{{ .TreeType }}) insertRightLeftBalancing(st []*node, g, d, n *node) {
+	t.rightRotate(append(st, g), d)
+	// the n becomes d after the rightRotate(d)
+	t.insertRightRightBalancing(st, g, n)
+}
+
+// balance tree after insert, the d is red, the st
+// is ancestors of the d
+func (t *{{ .TreeType }}) insertBalancing(st []*node, d, n *node) {
+	var g, u *node
+	for !t.isRoot(n) {
+		if !d.isRed() {
+			return
+		}
+		st, g = pop(st)
+		if u = g.opposite(d); u.isRed() {
+			g.pushBlack()
+			n = g
+			st, d = pop(st)
+			continue
+		}
+		// the u is black (or nil), not the loop
+		if g.l == d {
+			if d.l == n {
+				t.insertLeftLeftBalancing(st, g, d)
+			} else { // n is right
+				t.insertLeftRightBalancing(st, g, d, n)
+			}
+		} else { // d is right
+			if d.r == n {
+				t.insertRightRightBalancing(st, g, d)
+			} else { // n is left
+				t.insertRightLeftBalancing(st, g, d, n)
+			}
+		}
+		return // done
+	}
+	n.setBlack() // root must be black
+}
+
+// insert node to the tree and add pointer to it
+// to the d, the st is ancestors of the d
+func (t *{{ .TreeType }}) insertNode(st []*node, d, n *node) {
+	t.size++
+	if d == nil {
+		t.r = n     // first element of the tree
+		n.c = black // root must be black
+		return      // done
+	}
+	// required branch (left or right) is nil and
+	// its guarantee by findInsertNode
+	if {{ less "n.k" "d.k" }} {
+		d.l = n // left (less)
+	} else {
+		d.r = n // right (greater or equal)
+	}
+{{- if .Counted }}
+	addCount(st, 1)
+	d.s++
+{{- end }}
+	t.insertBalancing(st, d, n)
+}
+{{ else }}
+func (t *{{ .TreeType }}) rightRotate(n *node) {
+	var pivot = n.l
+	if n.d == nil {
+		t.r = pivot
+		pivot.c = black
+		pivot.d = nil
+	} else {
+		pivot.d = n.d
+		if n.isLeft() {
+			n.d.l = pivot
+		} else {
+			n.d.r = pivot
+		}
+	}
+	n.l = pivot.r
+	if pivot.r != nil {
+		pivot.r.d = n
+	}
+	n.d = pivot
+	pivot.r = n
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+}
+
+func (t *{{ .TreeType }}) leftRotate(n *node) {
+	var pivot = n.r
+	if n.d == nil {
+		t.r = pivot
+		pivot.c = black
+		pivot.d = nil
+	} else {
+		pivot.d = n.d
+		if n.isLeft() {
+			n.d.l = pivot
+		} else {
+			n.d.r = pivot
+		}
+	}
+	n.r = pivot.l
+	if pivot.l != nil {
+		pivot.l.d = n
+	}
+	n.d = pivot
+	pivot.l = n
+{{- if .Counted }}
+	n.recount()
+	pivot.recount()
+{{- end }}
+}
+
+func (t *{{ .TreeType }}) insertLeftLeftBalancing(g, d *node) {
+	d.c, g.c = g.c, d.c // swap colors
+	t.rightRotate(g)
+}
+
+func (t *{{ .TreeType }}) insertLeftRightBalancing(g, d, n *node) {
+	t.leftRotate(d)
+	// the n becomes d after the leftRotate(d)
+	t.insertLeftLeftBalancing(g, n)
+}
+
+func (t *{{ .TreeType }}) insertRightRightBalancing(g, d *node) {
+	d.c, g.c = g.c, d.c // swap colors
+	t.leftRotate(g)
+}
+
+func (t *{{ .TreeType }}) insertRightLeftBalancing(g, d, n *node) {
+	t.rightRotate(d)
+	// the n becomes d after the rightRotate(d)
+	t.insertRightRightBalancing(g, n)
+}
+
+// balance tree after insert, the d is red
+func (t *{{ .TreeType }}) insertBalancing(d, n *node) {
+	var g, u *node
+	for !t.isRoot(n) {
+		if !d.isRed() {
+			return
+		}
+		g = d.dad()
+		if u = n.uncle(); u.isRed() {
+			g.pushBlack()
+			d, n = g.dad(), g
+			continue
+		}
+		// the u is black (or nil), not the loop
+		if d.isLeft() {
+			if n.isLeft() {
+				t.insertLeftLeftBalancing(g, d)
+			} else { // n is right
+				t.insertLeftRightBalancing(g, d, n)
+			}
+		} else { // d is right
+			if n.isRight() {
+				t.insertRightRightBalancing(g, d)
+			} else { // n is left
+				t.insertRightLeftBalancing(g, d, n)
+			}
+		}
+		return // done
+	}
+	n.setBlack() // root must be black
+}
+
+// insert node to the tree and add pointer to it
+// to the d
+func (t *{{ .TreeType }}) insertNode(d, n *node) {
+	t.size++
+	if d == nil {
+		t.r = n     // first element of the tree
+		n.c = black // root must be black
+		return      // done
+	}
+	// required branch (left or right) is nil and
+	// its guarantee by findInsertNode
+	if {{ less "n.k" "d.k" }} {
+		d.l = n // left (less)
+	} else {
+		d.r = n // right (greater or equal)
+	}
+	n.d = d
+{{- if .Counted }}
+	addCount(d, 1)
+{{- end }}
+	t.insertBalancing(d, n)
+}
+{{ end -}}
+{{ end -}}
+{{ end }}
+
+{{- define "delete" }}
+{{- if .LeftLeaning }}
+// delete node with given key from subtree of the h returning
+// new root of the subtree and value of the deleted node; the
+// subtree must contain the key
+func (t *{{ .TreeType }}) delete(h *node, k {{ .Type }}) (_ *node, v {{ vtype }}) {
+	if {{ less "k" "h.k" }} {
+		if !h.l.isRed() && !h.l.l.isRed() {
+			h = h.moveRedLeft()
+		}
+		h.l, v = t.delete(h.l, k)
+		return h.fixUp(), v
+	}
+	if h.l.isRed() {
+		h = h.rotateRight()
+	}
+	if {{ equal "k" "h.k" }} && h.r == nil {
+		return nil, {{ val "h" }} // leaf
+	}
+	var x = h
+	if !h.r.isRed() && !h.r.l.isRed() {
+		h = h.moveRedRight()
+	}
+	// the h rotated by the moveRedRight can be a duplicate from
+	// the left, delete the x that is at the top of the h.r then
+	if h == x && {{ equal "k" "h.k" }} {
+		var min *node
+		v = {{ val "h" }}
+		h.r, min = h.r.deleteMin()
+		h.k{{ if .KeyValue }}, h.v = min.k, min.v{{ else }} = min.k{{ end }} // replace with successor
+	} else {
+		h.r, v = t.delete(h.r, k)
+	}
+	return h.fixUp(), v
+}
+
+// deleteNode by key returning value of the deleted
+// node; the tree must contain the key
+func (t *{{ .TreeType }}) deleteNode(k {{ .Type }}) (v {{ vtype }}) {
+	if !t.r.l.isRed() && !t.r.r.isRed() {
+		t.r.c = red
+	}
+	t.r, v = t.delete(t.r, k)
+	if t.r != nil {
+		t.r.c = black // root must be black
+	}
+	return
+}
+{{- else if .Stacked }}
+// the st is ancestors of the x
+func (t *{{ .TreeType }}) fixDoubleBlack(st []*node, x *node) {
+	var s, d *node
+	for {
+		if t.isRoot(x) {
+			return
+		}
+		st, d = pop(st)
+		if s = d.opposite(x); s == nil {
+			x = d
+			continue // no recursion
+		}
+		if s.isRed() {
+			d.c = red
+			s.c = black
+			if d.r == s {
+				t.leftRotate(st, d)
+			} else {
+				t.rightRotate(st, d)
+			}
+			st = append(st, s, d)
+			continue // no recursion
+		}
+		// the s is black
+		if s.hasRedChild() {
+			if s.r.isRed() {
+				if d.l == s {
+					s.r.c = d.c
+					t.leftRotate(append(st, d), s)
+					t.rightRotate(st, d)
+				} else {
+					s.r.c = s.c
+					s.c = d.c
+					t.leftRotate(st, d)
+				}
+			} else { // left is red
+				if d.l == s {
+					s.l.c = s.c
+					s.c = d.c
+					t.rightRotate(st, d)
+				} else {
+					s.l.c = d.c
+					t.rightRotate(append(st, d), s)
+					t.leftRotate(st, d)
+				}
+			}
+			d.c = black
+			return
+		}
+		s.c = red
+		if d.c == black {
+			x = d
+			continue
+		}
+		d.c = black
+		return
+	}
+}
+
+// delete and balance the tree, the st is ancestors of the v
+func (t *{{ .TreeType }}) delBalancing(st []*node, v *node) {
+	var (
+		d, u *node
+		ss   []*node
+	)
+	for {
+		ss, u = v.successor()
+		_, d = pop(st) // don't change the st
+		if u == nil {
+			if t.isRoot(v) {
+				t.r = nil
+				return
+			}
+{{- if .Counted }}
+			addCount(st, -1)
+			v.s = 0 // not counted by rotations
+{{- end }}
+			if v.isBlack() {
+				t.fixDoubleBlack(st, v)
+			} else {
+				if s := d.opposite(v); s != nil {
+					s.c = red
+				}
+			}
+			d.replaceChild(v, nil)
+			return
+		}
+		if v.l == nil || v.r == nil {
+			if t.isRoot(v) {
+				v.copy(u)
+				v.l, v.r = nil, nil
+{{- if .Counted }}
+				v.s = 1
+{{- end }}
+				return
+			}
+			d.replaceChild(v, u)
+{{- if .Counted }}
+			addCount(st, -1)
+{{- end }}
+			if u.isBlack() && v.isBlack() {
+				t.fixDoubleBlack(st, u)
+				return
+			}
+			u.c = black
+			return
+		}
+		v.copy(u)
+		v = u                  // no recursion
+		st = append(st, ss...) // ancestors of the u
+	}
+}
+{{- else }}
+func (t *{{ .TreeType }}) fixDoubleBlack(x *node) {
+	for {
+		if t.isRoot(x) {
+			return
+		}
+		var (
+			s = x.sibling()
+			d = x.d
+		)
+		if s == nil {
+			x = d
+			continue // no recursion
+		}
+		if s.isRed() {
+			d.c = red
+			s.c = black
+			if s.isRight() {
+				t.leftRotate(d)
+			} else {
+				t.rightRotate(d)
+			}
+			continue // no recursion
+		}
+		// the s is black
+		if s.hasRedChild() {
+			if s.r.isRed() {
+				if s.isLeft() {
+					s.r.c = d.c
+					t.leftRotate(s)
+					t.rightRotate(d)
+				} else {
+					s.r.c = s.c
+					s.c = d.c
+					t.leftRotate(d)
+				}
+			} else { // left is red
+				if s.isLeft() {
+					s.l.c = s.c
+					s.c = d.c
+					t.rightRotate(d)
+				} else {
+					s.l.c = d.c
+					t.rightRotate(s)
+					t.leftRotate(d)
+				}
+			}
+			d.c = black
+			return
+		}
+		s.c = red
+		if d.c == black {
+			x = d
+			continue
+		}
+		d.c = black
+		return
+	}
+}
+
+// delete and balance the tree
+func (t *{{ .TreeType }}) delBalancing(v *node) {
+	for {
+		var u = v.successor()
+		if u == nil {
+			if t.isRoot(v) {
+				t.r = nil
+				return
+			}
+{{- if .Counted }}
+			addCount(v.d, -1)
+			v.s = 0 // not counted by rotations
+{{- end }}
+			if v.isBlack() {
+				t.fixDoubleBlack(v)
+			} else {
+				if s := v.sibling(); s != nil {
+					s.c = red
+				}
+			}
+			v.d.replaceChild(v, nil)
+			return
+		}
+		if v.l == nil || v.r == nil {
+			if t.isRoot(v) {
+				v.copy(u)
+				v.l, v.r = nil, nil
+{{- if .Counted }}
+				v.s = 1
+{{- end }}
+				return
+			}
+			v.d.replaceChild(v, u)
+			u.d = v.d
+{{- if .Counted }}
+			addCount(u.d, -1)
+{{- end }}
+			if u.isBlack() && v.isBlack() {
+				t.fixDoubleBlack(u)
+				return
+			}
+			u.c = black
+			return
+		}
+		v.copy(u)
+		v = u // no recursion
+	}
+}
+{{- end }}
+{{ end }}
+
+{{- define "check" }}
+// testCheckBalance checks red-black properties of the tree
+func (t *{{ .TreeType }}) testCheckBalance(tb testing.TB) {
+	tb.Helper()
+	if t.r.isRed() {
+		tb.Fatal("red root")
+	}
+	var blacks func(n *node) int
+	blacks = func(n *node) (h int) {
+		if n == nil {
+			return 1
+		}
+		if n.isRed() && (n.l.isRed() || n.r.isRed()) {
+			tb.Fatal("red node has red child")
+		}
+{{- if .LeftLeaning }}
+		if n.r.isRed() {
+			tb.Fatal("right-leaning red link")
+		}
+{{- end }}
+		if h = blacks(n.l); h != blacks(n.r) {
+			tb.Fatal("different black heights")
+		}
+		if !n.isRed() {
+			h++
+		}
+		return
+	}
+	blacks(t.r)
+}
+{{ end }}
+`
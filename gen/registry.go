@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"flag"
+)
+
+// A Generator produces a data structure
+type Generator struct {
+	Name        string // name of the structure, like "rbtree"
+	Description string // short description, like "Red-black tree"
+
+	// Flags registers flags of options the structure supports
+	// in given set and sets default values of the options
+	Flags func(set *flag.FlagSet, opts *Options)
+	// Generate returns source code of the structure and
+	// its tests if the Options.Tests is set
+	Generate func(opts Options) ([]File, error)
+}
+
+// registered generators
+var generators []*Generator
+
+// Register makes a generator available by name of its structure;
+// it panics if a generator with the same name already registered
+func Register(g *Generator) {
+	if Lookup(g.Name) != nil {
+		panic("gen: generator " + g.Name + " already registered")
+	}
+	generators = append(generators, g)
+}
+
+// Lookup returns generator of structure with given name or nil
+func Lookup(name string) *Generator {
+	for _, g := range generators {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// Generators returns registered generators in order of registration
+func Generators() []*Generator {
+	return append([]*Generator(nil), generators...)
+}
@@ -0,0 +1,194 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// A Template is a named built-in template
+type Template struct {
+	Name string // name of the template and of the .tmpl file
+	Text string // the template
+}
+
+// built-in templates of given structure: the commonTemplate,
+// template of the structure and the testsTemplate
+func templateFiles(name, text string) []Template {
+	return []Template{
+		{"common", commonTemplate},
+		{name, text},
+		{"tests", testsTemplate},
+	}
+}
+
+// Templates returns built-in templates of given structure: the
+// "common" with blocks shared by all structures, template of the
+// structure and the "tests"; a file of the Options.Templates
+// directory overrides blocks of the template with the same name;
+// it returns nil if the structure has no built-in templates
+func Templates(structure string) []Template {
+	var s, ok = lookup(structure)
+	if !ok {
+		return nil
+	}
+	return templateFiles(s.name, s.text)
+}
+
+// parse built-in templates of given structure; if the dir is
+// not empty, then its common.tmpl, <structure>.tmpl and
+// tests.tmpl files override blocks of the built-in templates
+func parse(name, text string, funcs template.FuncMap,
+	dir string) (tmpl *template.Template, err error) {
+
+	tmpl = template.New(name).Funcs(funcs)
+	var files = templateFiles(name, text)
+	for _, tf := range files {
+		if _, err = tmpl.New(tf.Name).Parse(tf.Text); err != nil {
+			return nil, fmt.Errorf("parsing %s template: %v", tf.Name, err)
+		}
+	}
+	if dir == "" {
+		return
+	}
+	for _, tf := range files {
+		var path = filepath.Join(dir, tf.Name+".tmpl")
+		var user []byte
+		if user, err = ioutil.ReadFile(path); os.IsNotExist(err) {
+			continue // not overridden
+		} else if err != nil {
+			return nil, err
+		}
+		// an empty body (only blocks) keeps built-in one
+		if _, err = tmpl.New(tf.Name).Parse(string(user)); err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", path, err)
+		}
+	}
+	return tmpl, nil
+}
+
+// render template with given name and format the result
+// using gofmt rules removing unused imports
+func render(tmpl *template.Template, name string,
+	data interface{}) (src []byte, err error) {
+
+	var buf bytes.Buffer
+	if err = tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("executing %s template: %v", name, err)
+	}
+	if src, err = format.Source(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("formatting generated %s: %v", name, err)
+	}
+	if src, err = pruneImports(src); err != nil {
+		return nil, fmt.Errorf("formatting generated %s: %v", name, err)
+	}
+	return
+}
+
+// name of package of given import path, it's the last
+// element of the path without version suffix
+func importName(path string) (name string) {
+	var elems = strings.Split(path, "/")
+	name = elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(name) {
+		name = elems[len(elems)-2] // like 'github.com/user/pkg/v2'
+	}
+	if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i] // like 'gopkg.in/pkg.v2'
+	}
+	return strings.TrimPrefix(name, "go-")
+}
+
+// is given string like 'v2'
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// remove unused imports of given formatted source
+func pruneImports(src []byte) (pruned []byte, err error) {
+	var (
+		fset = token.NewFileSet()
+		file *ast.File
+	)
+	if file, err = parser.ParseFile(fset, "", src, 0); err != nil {
+		return
+	}
+	var used = make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	var lines = bytes.SplitAfter(src, []byte("\n"))
+	var drop = make(map[int]bool) // lines to drop
+	for _, spec := range file.Imports {
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else {
+			var path, _ = strconv.Unquote(spec.Path.Value)
+			name = importName(path)
+		}
+		if name == "_" || name == "." || used[name] {
+			continue
+		}
+		drop[fset.Position(spec.Pos()).Line-1] = true
+	}
+	if len(drop) == 0 {
+		return src, nil
+	}
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if !drop[i] {
+			buf.Write(line)
+		}
+	}
+	// an empty 'import ()' is valid, but ugly
+	var clean = bytes.Replace(buf.Bytes(), []byte("import (\n)\n"), nil, 1)
+	return format.Source(clean)
+}
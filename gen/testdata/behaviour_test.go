@@ -0,0 +1,246 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package p
+
+// the behaviour test is shared by all generated trees of the
+// golden tests, an adapter_test.go maps API of a tree to the
+// adapter methods
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// keys of the tests are 1..keyMax, zero is unbounded range
+const keyMax = 200
+
+// check keys and values of an iteration
+func checkPairs(t *testing.T, ks []int, vs []string, want []int) {
+	t.Helper()
+	if len(ks) != len(want) {
+		t.Fatalf("wrong keys %v, want %v", ks, want)
+	}
+	for i, k := range ks {
+		if k != want[i] {
+			t.Fatalf("wrong keys %v, want %v", ks, want)
+		}
+		if vs[i] != value(k) {
+			t.Fatalf("wrong value %q of key %d", vs[i], k)
+		}
+	}
+}
+
+// keys of given model in ascending order
+func modelKeys(m map[int]bool) (ks []int) {
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Ints(ks)
+	return
+}
+
+// check the tree has elements of the model
+func checkModel(t *testing.T, a adapter, m map[int]bool) {
+	t.Helper()
+	if a.size() != len(m) {
+		t.Fatalf("wrong size %d, want %d", a.size(), len(m))
+	}
+	var want = modelKeys(m)
+	var ks, vs = a.ascend(0, 0)
+	checkPairs(t, ks, vs, want)
+	ks, vs = a.walk() // without any order
+	var values = make(map[int]string, len(ks))
+	for i, k := range ks {
+		values[k] = vs[i]
+	}
+	sort.Ints(ks)
+	for i, k := range ks {
+		vs[i] = values[k]
+	}
+	checkPairs(t, ks, vs, want)
+	var min, minOk = a.min()
+	var max, maxOk = a.max()
+	if len(want) == 0 {
+		if minOk || maxOk {
+			t.Fatal("min or max of empty tree")
+		}
+		return
+	}
+	if !minOk || min != want[0] {
+		t.Fatalf("wrong min %d, %t, want %d", min, minOk, want[0])
+	}
+	if !maxOk || max != want[len(want)-1] {
+		t.Fatalf("wrong max %d, %t, want %d", max, maxOk, want[len(want)-1])
+	}
+}
+
+func TestModel(t *testing.T) {
+	var (
+		a   = newAdapter()
+		m   = make(map[int]bool)
+		rnd = rand.New(rand.NewSource(1))
+	)
+	for i := 0; i < 10000; i++ {
+		var k = 1 + rnd.Intn(keyMax)
+		switch op := rnd.Intn(5); op {
+		case 0:
+			if a.ins(k) == m[k] {
+				t.Fatalf("ins %d: wrong result", k)
+			}
+			m[k] = true
+		case 1:
+			if a.insNx(k) == m[k] {
+				t.Fatalf("insNx %d: wrong result", k)
+			}
+			m[k] = true
+		case 2:
+			if a.insEx(k) != m[k] {
+				t.Fatalf("insEx %d: wrong result", k)
+			}
+		case 3:
+			var v, ok = a.del(k)
+			if ok != m[k] || ok && v != value(k) {
+				t.Fatalf("del %d: wrong result %q, %t", k, v, ok)
+			}
+			delete(m, k)
+		case 4:
+			var v, ok = a.get(k)
+			if ok != m[k] || ok && v != value(k) {
+				t.Fatalf("get %d: wrong result %q, %t", k, v, ok)
+			}
+		}
+		if i%100 == 0 {
+			checkModel(t, a, m)
+		}
+	}
+	checkModel(t, a, m)
+	a.clear()
+	checkModel(t, a, nil)
+}
+
+func TestRange(t *testing.T) {
+	var a = newAdapter()
+	for _, k := range rand.New(rand.NewSource(1)).Perm(keyMax / 2) {
+		a.ins(2 * (k + 1)) // even keys
+	}
+	// bounds are zero or keys of the tree
+	for from := 0; from <= keyMax; from += 6 {
+		for to := 0; to <= keyMax; to += 10 {
+			var asc, desc []int
+			for k := 2; k <= keyMax; k += 2 {
+				if (from == 0 || k >= from) && (to == 0 || k <= to) {
+					asc = append(asc, k)
+				}
+				if (from == 0 || k <= from) && (to == 0 || k >= to) {
+					desc = append([]int{k}, desc...)
+				}
+			}
+			var ks, vs = a.ascend(from, to)
+			checkPairs(t, ks, vs, asc)
+			ks, vs = a.descend(from, to)
+			checkPairs(t, ks, vs, desc)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	if unique {
+		t.Skip("unique tree")
+	}
+	var a = newAdapter()
+	for i := 0; i < 3; i++ {
+		for k := 1; k <= keyMax; k++ {
+			if a.add(k) != (i == 0) {
+				t.Fatalf("add %d: wrong result", k)
+			}
+		}
+	}
+	if a.size() != 3*keyMax {
+		t.Fatalf("wrong size %d", a.size())
+	}
+	var ks, _ = a.ascend(10, 11)
+	if len(ks) != 6 {
+		t.Fatalf("wrong non-unique keys %v", ks)
+	}
+	for k := 1; k <= keyMax; k++ {
+		for i := 0; i < 3; i++ {
+			if _, ok := a.del(k); !ok {
+				t.Fatalf("del %d: missing", k)
+			}
+		}
+	}
+	checkModel(t, a, nil)
+}
+
+func TestPrint(t *testing.T) {
+	if !hasPrint {
+		t.Skip("no Print method")
+	}
+	var a = newAdapter()
+	for k := 1; k <= 20; k++ {
+		a.ins(k)
+	}
+	var lines = strings.Split(strings.TrimSpace(a.print()), "\n")
+	var nodes int
+	for _, line := range lines[1:] {
+		if !strings.HasSuffix(line, "-- nil") {
+			nodes++
+		}
+	}
+	if nodes != a.size() {
+		t.Fatalf("wrong number of printed nodes %d\n%s", nodes, a.print())
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	if !threadSafe {
+		t.Skip("not thread-safe tree")
+	}
+	var (
+		a  = newAdapter()
+		wg sync.WaitGroup
+	)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for k := 1 + g; k <= keyMax; k += 4 {
+				a.ins(k)
+				a.get(k)
+				a.ascend(0, k)
+			}
+		}(g)
+	}
+	wg.Wait()
+	var m = make(map[int]bool)
+	for k := 1; k <= keyMax; k++ {
+		m[k] = true
+	}
+	checkModel(t, a, m)
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package variants
+
+// the variants test runs the same random changes against
+// trees with parent pointers and their stacked variants
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type variant interface {
+	Ins(k int, v string) (p string, ok bool)
+	InsNx(k int, v string) (e string, ok bool)
+	InsEx(k int, v string) (p string, ok bool)
+	Add(k int, v string) (ok bool)
+	Del(k int) (v string, ok bool)
+	Size() int
+}
+
+// elements of a tree in ascending order
+type walker func() string
+
+func collect(b *strings.Builder) func(k int, v string) bool {
+	return func(k int, v string) bool {
+		fmt.Fprintf(b, "%d:%s ", k, v)
+		return true
+	}
+}
+
+func testVariants(t *testing.T, dad, stacked variant, dw, sw walker) {
+	t.Helper()
+	var rnd = rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var (
+			k        = rnd.Intn(64) + 1
+			v        = strconv.Itoa(i)
+			op       string
+			dv, sv   string
+			dok, sok bool
+		)
+		switch rnd.Intn(5) {
+		case 0:
+			op = "Ins"
+			dv, dok = dad.Ins(k, v)
+			sv, sok = stacked.Ins(k, v)
+		case 1:
+			op = "InsNx"
+			dv, dok = dad.InsNx(k, v)
+			sv, sok = stacked.InsNx(k, v)
+		case 2:
+			op = "InsEx"
+			dv, dok = dad.InsEx(k, v)
+			sv, sok = stacked.InsEx(k, v)
+		case 3:
+			op = "Add"
+			dok, sok = dad.Add(k, v), stacked.Add(k, v)
+		default:
+			op = "Del"
+			dv, dok = dad.Del(k)
+			sv, sok = stacked.Del(k)
+		}
+		if dv != sv || dok != sok {
+			t.Fatalf("%d: %s(%d) returns (%q, %t), stacked (%q, %t)", i, op,
+				k, dv, dok, sv, sok)
+		}
+		if dad.Size() != stacked.Size() {
+			t.Fatalf("%d: %s(%d): size %d, stacked %d", i, op, k,
+				dad.Size(), stacked.Size())
+		}
+		if d, s := dw(), sw(); d != s {
+			t.Fatalf("%d: %s(%d): walk\n\t%s\nstacked\n\t%s", i, op, k, d, s)
+		}
+	}
+}
+
+func TestRBTree_stacked(t *testing.T) {
+	var dad, stacked = NewRBTree(), NewRBStackedTree()
+	testVariants(t, dad, stacked, func() string {
+		var b strings.Builder
+		dad.Walk(collect(&b))
+		return b.String()
+	}, func() string {
+		var b strings.Builder
+		stacked.Walk(collect(&b))
+		return b.String()
+	})
+}
+
+func TestAVLTree_stacked(t *testing.T) {
+	var dad, stacked = NewAVLTree(), NewAVLStackedTree()
+	testVariants(t, dad, stacked, func() string {
+		var b strings.Builder
+		dad.Walk(collect(&b))
+		return b.String()
+	}, func() string {
+		var b strings.Builder
+		stacked.Walk(collect(&b))
+		return b.String()
+	})
+}
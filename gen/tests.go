@@ -0,0 +1,1109 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// check options of tests and set default conversions
+// of test keys and values
+func (r *rbTree) validateTests() (err error) {
+	if !r.Tests {
+		return
+	}
+	if r.Output == "" {
+		return optionErr("tests", errors.New("requires output file"))
+	}
+	if r.TestKey == "" {
+		if r.TestKey, err = testConversion(r.Type, r.keyType); err != nil {
+			return optionErr("test-key", err)
+		}
+	}
+	if r.KeyValue && r.TestValue == "" {
+		if r.TestValue, err = testConversion(r.Value, r.valueType); err != nil {
+			return optionErr("test-value", err)
+		}
+	}
+	return
+}
+
+// default format converting int to given type
+// keeping order, for numbers and strings only
+func testConversion(name string, typ types.Type) (conv string, err error) {
+	var basic *types.Basic
+	if typ != nil {
+		basic, _ = typ.Underlying().(*types.Basic)
+	}
+	switch {
+	case basic == nil:
+	case basic.Info()&(types.IsInteger|types.IsFloat) != 0:
+		return name + "(%s)", nil
+	case basic.Info()&types.IsString != 0 && name == "string":
+		return `fmt.Sprintf("%%09d", %s)`, nil
+	case basic.Info()&types.IsString != 0:
+		return name + `(fmt.Sprintf("%%09d", %s))`, nil
+	}
+	return "", fmt.Errorf("can't convert int to %s automatically, "+
+		"provide format like 'pkg.NewKey(%%s)'", name)
+}
+
+// testOutput is name of the _test.go file
+func (r *rbTree) testOutput() string {
+	return strings.TrimSuffix(r.Output, ".go") + "_test.go"
+}
+
+// TestImportList returns sorted list of imports of
+// tests, unused imports removed after
+func (r *rbTree) TestImportList() (list []string) {
+	list = []string{"fmt", "math/rand", "reflect", "sort", "strings",
+		"testing"}
+	for _, path := range r.ImportList() {
+		if !Strings(list).Contain(path) {
+			list = append(list, path)
+		}
+	}
+	sort.Strings(list)
+	return
+}
+
+// The testsTemplate is tests and benchmarks of a generated
+// tree. Keys and values of the tests are produced by the
+// -test-key and -test-value formats from integers. Free
+// functions of tests compare keys using the testLess and
+// the testEqual, that are concrete for a generic tree. The
+// tree template defines testCheckBalance method in the
+// "check" block. The "extraTests" block is empty, a user
+// can override it to test extra methods.
+const testsTemplate = `{{ template "generated" . }}
+package {{ .Package }}
+
+import (
+{{- range .TestImportList }}
+	"{{ . }}"
+{{- end }}
+)
+{{ if .Generic }}
+// tests use {{ .TreeType }}[int, string]
+type (
+	K = int
+	V = string
+)
+{{ end }}
+const (
+	testKeyMin = 1   // the zero key means unbounded range
+	testKeyMax = 100 //
+)
+
+// int to key, the conversion keeps order
+func testKey(i int) {{ .Type }} {
+	return {{ testKeyExpr "i" }}
+}
+{{ if .KeyValue }}
+// int to value
+func testValue(i int) {{ .Value }} {
+	return {{ testValueExpr "i" }}
+}
+{{ end }}
+// testBound is key of Ascend or Descend, the zero is unbounded
+func testBound(i int) (k {{ .Type }}) {
+	if i != 0 {
+		k = testKey(i)
+	}
+	return
+}
+
+func testSameKey(a, b {{ .Type }}) bool {
+	return {{ testEqual "a" "b" }}
+}
+
+func testSame(a, b {{ vtype }}) bool {
+{{- if .KeyValue }}
+	return reflect.DeepEqual(a, b)
+{{- else }}
+	return testSameKey(a, b)
+{{- end }}
+}
+
+// [f, t] or [t, f] (reversed)
+func testRange(f, t int, random bool) (vs []int) {
+	var step = 1
+	if f > t {
+		step = -1
+	}
+	for i := f; i != t+step; i += step {
+		vs = append(vs, i)
+	}
+	if random {
+		rand.Shuffle(len(vs), func(i, j int) {
+			vs[i], vs[j] = vs[j], vs[i]
+		})
+	}
+	return
+}
+
+var testRanges = [][]int{
+	testRange(testKeyMin, testKeyMax, false),
+	testRange(testKeyMax, testKeyMin, false),
+	testRange(testKeyMin, testKeyMax, true),
+	testRange(testKeyMax, testKeyMin, true),
+}
+
+func testRangeString(r []int) string {
+	return fmt.Sprintf("[%d, ..., %d] %d", r[0], r[len(r)-1], len(r))
+}
+
+func testFill(r []int) (tr *{{ .TreeType }}) {
+	tr = {{ .New }}()
+	for _, i := range r {
+		tr.Ins({{ testArgs "i" "i" }})
+	}
+	return
+}
+
+// testCheck checks {{ if not .Stacked }}parent references, {{ end }}order of
+// keys, size{{ if .Counted }}, sizes of subtrees{{ end }} and balance of the tree
+func (t *{{ .TreeType }}) testCheck(tb testing.TB) {
+	tb.Helper()
+{{- if not .Stacked }}
+	if t.r != nil && t.r.d != nil {
+		tb.Fatal("root has dad")
+	}
+{{- end }}
+	var (
+		size int
+		prev *node
+		walk func(n *node)
+	)
+	walk = func(n *node) {
+		if n == nil {
+			return
+		}
+{{- if not .Stacked }}
+		if n.l != nil && n.l.d != n || n.r != nil && n.r.d != n {
+			tb.Fatal("broken dad reference")
+		}
+{{- end }}
+		walk(n.l)
+{{- if .Unique }}
+		if prev != nil && !({{ less "prev.k" "n.k" }}) {
+{{- else }}
+		if prev != nil && {{ less "n.k" "prev.k" }} {
+{{- end }}
+			tb.Fatal("wrong order of keys", prev.k, n.k)
+		}
+		prev, size = n, size+1
+		walk(n.r)
+	}
+	walk(t.r)
+	if size != t.size {
+		tb.Fatal("wrong size", t.size, "want", size)
+	}
+{{- if .Counted }}
+	var count func(n *node) int
+	count = func(n *node) (s int) {
+		if n == nil {
+			return 0
+		}
+		if s = count(n.l) + count(n.r) + 1; s != n.s {
+			tb.Fatal("wrong size of subtree", n.k, n.s, "want", s)
+		}
+		return
+	}
+	count(t.r)
+{{- end }}
+	t.testCheckBalance(tb)
+}
+
+{{ template "check" . }}
+
+func Test{{ .TreeType }}_Ins(t *testing.T) {
+	var zero {{ vtype }}
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		for _, i := range r {
+			if p, ok := tr.Ins({{ testArgs "i" "i" }}); !ok {
+				t.Fatal("ok is false", i, testRangeString(r))
+			} else if !testSame(p, zero) {
+				t.Fatal("p is not zero", i, p)
+			}
+			tr.testCheck(t)
+		}
+		if tr.Size() != len(r) {
+			t.Fatal("wrong size", tr.Size(), "want", len(r))
+		}
+		for _, i := range r {
+			if p, ok := tr.Ins({{ testArgs "i" "i+testKeyMax" }}); ok {
+				t.Fatal("ok is true", i, testRangeString(r))
+			} else if !testSame(p, {{ testVal "i" "i" }}) {
+				t.Fatal("wrong p", i, p)
+			}
+			if v, ok := tr.Get(testKey(i)); !ok {
+				t.Fatal("not found", i)
+			} else if !testSame(v, {{ testVal "i" "i+testKeyMax" }}) {
+				t.Fatal("not overwritten", i, v)
+			}
+		}
+		if tr.Size() != len(r) {
+			t.Fatal("wrong size", tr.Size(), "want", len(r))
+		}
+		tr.testCheck(t)
+	}
+}
+
+func Test{{ .TreeType }}_InsNx(t *testing.T) {
+	var zero {{ vtype }}
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		for _, i := range r {
+			if e, ok := tr.InsNx({{ testArgs "i" "i" }}); !ok {
+				t.Fatal("ok is false", i, testRangeString(r))
+			} else if !testSame(e, zero) {
+				t.Fatal("e is not zero", i, e)
+			}
+			tr.testCheck(t)
+		}
+		for _, i := range r {
+			if e, ok := tr.InsNx({{ testArgs "i" "i+testKeyMax" }}); ok {
+				t.Fatal("ok is true", i, testRangeString(r))
+			} else if !testSame(e, {{ testVal "i" "i" }}) {
+				t.Fatal("wrong e", i, e)
+			}
+			if v, _ := tr.Get(testKey(i)); !testSame(v, {{ testVal "i" "i" }}) {
+				t.Fatal("overwritten", i, v)
+			}
+		}
+		if tr.Size() != len(r) {
+			t.Fatal("wrong size", tr.Size(), "want", len(r))
+		}
+		tr.testCheck(t)
+	}
+}
+
+func Test{{ .TreeType }}_InsEx(t *testing.T) {
+	var zero {{ vtype }}
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		for _, i := range r {
+			if p, ok := tr.InsEx({{ testArgs "i" "i" }}); ok {
+				t.Fatal("ok is true", i, testRangeString(r))
+			} else if !testSame(p, zero) {
+				t.Fatal("p is not zero", i, p)
+			}
+		}
+		if tr.Size() != 0 {
+			t.Fatal("wrong size", tr.Size(), "want 0")
+		}
+		tr = testFill(r)
+		for _, i := range r {
+			if p, ok := tr.InsEx({{ testArgs "i" "i+testKeyMax" }}); !ok {
+				t.Fatal("ok is false", i, testRangeString(r))
+			} else if !testSame(p, {{ testVal "i" "i" }}) {
+				t.Fatal("wrong p", i, p)
+			}
+			if v, _ := tr.Get(testKey(i)); !testSame(v, {{ testVal "i" "i+testKeyMax" }}) {
+				t.Fatal("not overwritten", i, v)
+			}
+		}
+		if tr.Size() != len(r) {
+			t.Fatal("wrong size", tr.Size(), "want", len(r))
+		}
+		tr.testCheck(t)
+	}
+}
+{{ if not .Unique }}
+func Test{{ .TreeType }}_Add(t *testing.T) {
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		for _, i := range r {
+			if !tr.Add({{ testArgs "i" "i" }}) {
+				t.Fatal("ok is false", i, testRangeString(r))
+			}
+			tr.testCheck(t)
+		}
+		for _, i := range r {
+			if tr.Add({{ testArgs "i" "i+testKeyMax" }}) {
+				t.Fatal("ok is true", i, testRangeString(r))
+			}
+			tr.testCheck(t)
+		}
+		if tr.Size() != 2*len(r) {
+			t.Fatal("wrong size", tr.Size(), "want", 2*len(r))
+		}
+		var called int
+		tr.Ascend(testBound(0), testBound(0), func({{ params }}) bool {
+			if i := testKeyMin + called/2; !testSameKey(k, testKey(i)) {
+				t.Fatal("wrong key", k, "want", testKey(i))
+			}
+			called++
+			return true
+		})
+		if called != tr.Size() {
+			t.Fatal("wrong called", called, "want", tr.Size())
+		}
+		for _, i := range r {
+			for n := 0; n < 2; n++ {
+				if _, ok := tr.Del(testKey(i)); !ok {
+					t.Fatal("not deleted", i, n)
+				}
+				tr.testCheck(t)
+			}
+		}
+		if tr.Size() != 0 {
+			t.Fatal("wrong size", tr.Size(), "want 0")
+		}
+	}
+}
+{{ end }}
+func Test{{ .TreeType }}_Get(t *testing.T) {
+	var zero {{ vtype }}
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		if v, ok := tr.Get(testKey(testKeyMin)); ok {
+			t.Fatal("ok is true")
+		} else if !testSame(v, zero) {
+			t.Fatal("v is not zero", v)
+		}
+		tr = testFill(r)
+		for _, i := range r {
+			if v, ok := tr.Get(testKey(i)); !ok {
+				t.Fatal("not found", i, testRangeString(r))
+			} else if !testSame(v, {{ testVal "i" "i" }}) {
+				t.Fatal("wrong v", i, v)
+			}
+		}
+		if _, ok := tr.Get(testKey(testKeyMax + 1)); ok {
+			t.Fatal("found missing key")
+		}
+	}
+}
+
+func Test{{ .TreeType }}_Del(t *testing.T) {
+	var zero {{ vtype }}
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		if v, ok := tr.Del(testKey(testKeyMin)); ok {
+			t.Fatal("ok is true")
+		} else if !testSame(v, zero) {
+			t.Fatal("v is not zero", v)
+		}
+		for _, d := range testRanges {
+			tr = testFill(r)
+			for n, i := range d {
+				if v, ok := tr.Del(testKey(i)); !ok {
+					t.Fatal("not deleted", i, testRangeString(r),
+						testRangeString(d))
+				} else if !testSame(v, {{ testVal "i" "i" }}) {
+					t.Fatal("wrong v", i, v)
+				}
+				if _, ok := tr.Get(testKey(i)); ok {
+					t.Fatal("found deleted", i)
+				}
+				if _, ok := tr.Del(testKey(i)); ok {
+					t.Fatal("deleted twice", i)
+				}
+				if tr.Size() != len(d)-n-1 {
+					t.Fatal("wrong size", tr.Size(), "want", len(d)-n-1)
+				}
+				tr.testCheck(t)
+			}
+		}
+	}
+}
+
+func Test{{ .TreeType }}_Min(t *testing.T) {
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		if _, {{ if .KeyValue }}_, {{ end }}ok := tr.Min(); ok {
+			t.Fatal("ok is true")
+		}
+		var lo = r[0]
+		for _, i := range r {
+			tr.Ins({{ testArgs "i" "i" }})
+			if i < lo {
+				lo = i
+			}
+			var k, {{ if .KeyValue }}v, {{ end }}ok = tr.Min()
+			if !ok {
+				t.Fatal("ok is false", i, testRangeString(r))
+			}
+			if !testSameKey(k, testKey(lo)) {
+				t.Fatal("wrong min", k, "want", testKey(lo))
+			}
+{{- if .KeyValue }}
+			if !testSame(v, testValue(lo)) {
+				t.Fatal("wrong v", v, "want", testValue(lo))
+			}
+{{- end }}
+		}
+	}
+}
+
+func Test{{ .TreeType }}_Max(t *testing.T) {
+	for _, r := range testRanges {
+		var tr = {{ .New }}()
+		if _, {{ if .KeyValue }}_, {{ end }}ok := tr.Max(); ok {
+			t.Fatal("ok is true")
+		}
+		var hi = r[0]
+		for _, i := range r {
+			tr.Ins({{ testArgs "i" "i" }})
+			if i > hi {
+				hi = i
+			}
+			var k, {{ if .KeyValue }}v, {{ end }}ok = tr.Max()
+			if !ok {
+				t.Fatal("ok is false", i, testRangeString(r))
+			}
+			if !testSameKey(k, testKey(hi)) {
+				t.Fatal("wrong max", k, "want", testKey(hi))
+			}
+{{- if .KeyValue }}
+			if !testSame(v, testValue(hi)) {
+				t.Fatal("wrong v", v, "want", testValue(hi))
+			}
+{{- end }}
+		}
+	}
+}
+
+func Test{{ .TreeType }}_Size(t *testing.T) {
+	var tr = {{ .New }}()
+	if tr.Size() != 0 {
+		t.Fatal("size is not zero")
+	}
+	for n, i := range testRanges[2] {
+		tr.Ins({{ testArgs "i" "i" }})
+		if tr.Size() != n+1 {
+			t.Fatal("wrong size", tr.Size(), "want", n+1)
+		}
+	}
+}
+
+func Test{{ .TreeType }}_Clear(t *testing.T) {
+	var tr = testFill(testRanges[2])
+	tr.Clear()
+	if tr.Size() != 0 {
+		t.Fatal("size is not zero")
+	}
+	if _, ok := tr.Get(testKey(testKeyMin)); ok {
+		t.Fatal("found after clear")
+	}
+	tr.testCheck(t)
+}
+
+func Test{{ .TreeType }}_Walk(t *testing.T) {
+	var tr = {{ .New }}()
+	var called int
+	tr.Walk(func({{ params }}) bool {
+		called++
+		return true
+	})
+	if called != 0 {
+		t.Fatal("wrong called", called)
+	}
+	tr = testFill(testRanges[2])
+	var (
+		ks []{{ .Type }}
+		vs []{{ vtype }}
+	)
+	tr.Walk(func({{ params }}) bool {
+		ks, vs = append(ks, k), append(vs, {{ arg }})
+		return true
+	})
+	if len(ks) != tr.Size() {
+		t.Fatal("wrong called", len(ks), "want", tr.Size())
+	}
+	for i, k := range ks {
+		if v, ok := tr.Get(k); !ok {
+			t.Fatal("walk unknown key", k)
+		} else if !testSame(v, vs[i]) {
+			t.Fatal("wrong v", v)
+		}
+	}
+	called = 0
+	tr.Walk(func({{ params }}) bool {
+		called++
+		return false
+	})
+	if called != 1 {
+		t.Fatal("wrong called", called, "want 1")
+	}
+}
+
+{{ if .Printer -}}
+func Test{{ .TreeType }}_Print(t *testing.T) {
+	if s := {{ .New }}().String(); s != "{{ .TreeType }}\n" {
+		t.Fatalf("empty tree printed as %q", s)
+	}
+	var tr = testFill(testRanges[2])
+	var lines = strings.Split(strings.TrimSuffix(tr.String(), "\n"), "\n")
+	var nodes int
+	for _, line := range lines[1:] {
+		if !strings.HasSuffix(line, "-- nil") {
+			nodes++
+		}
+	}
+	if nodes != tr.Size() {
+		t.Fatal("wrong number of printed nodes", nodes, "want", tr.Size())
+	}
+}
+
+{{ end -}}
+// ascending or descending iteration
+func testIterate(t *testing.T, descend bool) {
+	var tr = testFill(testRanges[2])
+	for from := 0; from <= testKeyMax; from++ {
+		for to := 0; to <= testKeyMax; to++ {
+			var want []int
+			if descend {
+				var hi, lo = testKeyMax, testKeyMin
+				if from != 0 {
+					hi = from
+				}
+				if to != 0 {
+					lo = to
+				}
+				for i := hi; i >= lo; i-- {
+					want = append(want, i)
+				}
+			} else {
+				var lo, hi = testKeyMin, testKeyMax
+				if from != 0 {
+					lo = from
+				}
+				if to != 0 {
+					hi = to
+				}
+				for i := lo; i <= hi; i++ {
+					want = append(want, i)
+				}
+			}
+			var called int
+			var iterFunc = func({{ params }}) bool {
+				if called >= len(want) {
+					t.Fatal("too many elements", from, to)
+				}
+				var i = want[called]
+				if !testSameKey(k, testKey(i)) {
+					t.Fatal("wrong key", k, "want", testKey(i), from, to)
+				}
+{{- if .KeyValue }}
+				if !testSame(v, testValue(i)) {
+					t.Fatal("wrong v", v, "want", testValue(i), from, to)
+				}
+{{- end }}
+				called++
+				return true
+			}
+			if descend {
+				tr.Descend(testBound(from), testBound(to), iterFunc)
+			} else {
+				tr.Ascend(testBound(from), testBound(to), iterFunc)
+			}
+			if called != len(want) {
+				t.Fatal("wrong called", called, "want", len(want), from, to)
+			}
+		}
+	}
+	var called int
+	tr.Ascend(testBound(0), testBound(0), func({{ params }}) bool {
+		called++
+		return false
+	})
+	tr.Descend(testBound(0), testBound(0), func({{ params }}) bool {
+		called++
+		return false
+	})
+	if called != 2 {
+		t.Fatal("wrong called", called, "want 2")
+	}
+}
+
+func Test{{ .TreeType }}_Ascend(t *testing.T) {
+	testIterate(t, false)
+}
+
+func Test{{ .TreeType }}_Descend(t *testing.T) {
+	testIterate(t, true)
+}
+
+// testRangeBound of given kind: unbounded, inclusive or exclusive
+func testRangeBound(kind, i int) {{ bound }} {
+	switch kind {
+	case 1:
+		return Inclusive(testKey(i))
+	case 2:
+		return Exclusive(testKey(i))
+	}
+	return {{ if .Generic }}{{ bound }}{}{{ else }}Unbounded{{ end }}
+}
+
+// is the i in range of lower bound of the lk kind and the
+// l key and upper bound of the hk kind and the h key
+func testInRange(lk, l, hk, h, i int) bool {
+	return (lk == 0 || lk == 1 && i >= l || lk == 2 && i > l) &&
+		(hk == 0 || hk == 1 && i <= h || hk == 2 && i < h)
+}
+
+// bounds of ranges are keys below, above and between
+// keys of the tree, including the zero key
+func testIterateRange(t *testing.T, descend bool) {
+	var tr = testFill(testRanges[2])
+	for lk := 0; lk <= 2; lk++ {
+		for l := testKeyMin - 1; l <= testKeyMax+1; l++ {
+			for hk := 0; hk <= 2; hk++ {
+				for h := testKeyMin - 1; h <= testKeyMax+1; h++ {
+					var want []int
+					for i := testKeyMin; i <= testKeyMax; i++ {
+						if testInRange(lk, l, hk, h, i) {
+							want = append(want, i)
+						}
+					}
+					var called int
+					var iterFunc = func({{ params }}) bool {
+						if called >= len(want) {
+							t.Fatal("too many elements", lk, l, hk, h)
+						}
+						var i = want[called]
+						if descend {
+							i = want[len(want)-1-called]
+						}
+						if !testSameKey(k, testKey(i)) {
+							t.Fatal("wrong key", k, "want", testKey(i), lk, l, hk, h)
+						}
+{{- if .KeyValue }}
+						if !testSame(v, testValue(i)) {
+							t.Fatal("wrong v", v, "want", testValue(i), lk, l, hk, h)
+						}
+{{- end }}
+						called++
+						return true
+					}
+					var lo, hi = testRangeBound(lk, l), testRangeBound(hk, h)
+					if descend {
+						tr.DescendRange(hi, lo, iterFunc)
+					} else {
+						tr.AscendRange(lo, hi, iterFunc)
+					}
+					if called != len(want) {
+						t.Fatal("wrong called", called, "want", len(want), lk, l, hk, h)
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test{{ .TreeType }}_AscendRange(t *testing.T) {
+	testIterateRange(t, false)
+}
+
+func Test{{ .TreeType }}_DescendRange(t *testing.T) {
+	testIterateRange(t, true)
+}
+{{ if .Counted }}
+// tree of even keys of the testRanges
+func testFillEven(r []int) (tr *{{ .TreeType }}) {
+	tr = {{ .New }}()
+	for _, i := range r {
+		if i%2 == 0 {
+			tr.Ins({{ testArgs "i" "i" }})
+		}
+	}
+	return
+}
+
+func Test{{ .TreeType }}_Rank(t *testing.T) {
+	for _, r := range testRanges {
+		var tr = testFillEven(r)
+		tr.testCheck(t)
+		for i := testKeyMin - 1; i <= testKeyMax+1; i++ {
+			var want int
+			for k := testKeyMin; k < i; k++ {
+				if k%2 == 0 {
+					want++
+				}
+			}
+			if got := tr.Rank(testKey(i)); got != want {
+				t.Fatal("wrong rank", i, got, "want", want, testRangeString(r))
+			}
+		}
+	}
+}
+
+func Test{{ .TreeType }}_Select(t *testing.T) {
+	for _, r := range testRanges {
+		var tr = testFillEven(r)
+		for i := -1; i <= tr.Size(); i++ {
+{{- if .KeyValue }}
+			var k, v, ok = tr.Select(i)
+{{- else }}
+			var k, ok = tr.Select(i)
+{{- end }}
+			if i < 0 || i >= tr.Size() {
+				if ok {
+					t.Fatal("select out of the tree", i, k, testRangeString(r))
+				}
+				continue
+			}
+			var want = testKeyMin + testKeyMin%2 + i*2 // even keys
+			if !ok || !testSameKey(k, testKey(want)) {
+				t.Fatal("wrong select", i, k, ok, "want", want, testRangeString(r))
+			}
+{{- if .KeyValue }}
+			if !testSame(v, testValue(want)) {
+				t.Fatal("wrong value", i, v, "want", testValue(want))
+			}
+{{- end }}
+		}
+	}
+}
+
+func Test{{ .TreeType }}_CountRange(t *testing.T) {
+	var tr = testFillEven(testRanges[2])
+	for lk := 0; lk <= 2; lk++ {
+		for l := testKeyMin - 1; l <= testKeyMax+1; l++ {
+			for hk := 0; hk <= 2; hk++ {
+				for h := testKeyMin - 1; h <= testKeyMax+1; h++ {
+					var want int
+					for i := testKeyMin; i <= testKeyMax; i++ {
+						if i%2 == 0 && testInRange(lk, l, hk, h, i) {
+							want++
+						}
+					}
+					var lo, hi = testRangeBound(lk, l), testRangeBound(hk, h)
+					if got := tr.CountRange(lo, hi); got != want {
+						t.Fatal("wrong count", lk, l, hk, h, got, "want", want)
+					}
+				}
+			}
+		}
+	}
+}
+
+{{- end }}
+
+// random operations compared with a map
+func Test{{ .TreeType }}_random(t *testing.T) {
+	var (
+		tr   = {{ .New }}()
+		m    = make(map[int]int) // key -> value
+		zero {{ vtype }}
+	)
+	for n := 0; n < 10000; n++ {
+		var i, j = testKeyMin + rand.Intn(testKeyMax), rand.Intn(testKeyMax)
+		var _, had = m[i]
+		var want = zero
+		if had {
+			want = {{ testVal "i" "m[i]" }}
+		}
+		var v {{ vtype }}
+		var ok bool
+		switch rand.Intn(5) {
+		case 0:
+			if v, ok = tr.Ins({{ testArgs "i" "j" }}); ok == had {
+				t.Fatal("Ins: wrong ok", i, ok)
+			}
+			m[i] = j
+		case 1:
+			if v, ok = tr.InsNx({{ testArgs "i" "j" }}); ok == had {
+				t.Fatal("InsNx: wrong ok", i, ok)
+			}
+			if !had {
+				m[i] = j
+			}
+		case 2:
+			if v, ok = tr.InsEx({{ testArgs "i" "j" }}); ok != had {
+				t.Fatal("InsEx: wrong ok", i, ok)
+			}
+			if had {
+				m[i] = j
+			}
+		case 3:
+			if v, ok = tr.Del(testKey(i)); ok != had {
+				t.Fatal("Del: wrong ok", i, ok)
+			}
+			delete(m, i)
+		case 4:
+			if v, ok = tr.Get(testKey(i)); ok != had {
+				t.Fatal("Get: wrong ok", i, ok)
+			}
+		}
+		if !testSame(v, want) {
+			t.Fatal("wrong result", v, "want", want)
+		}
+		if tr.Size() != len(m) {
+			t.Fatal("wrong size", tr.Size(), "want", len(m))
+		}
+		tr.testCheck(t)
+		if n%100 == 0 {
+			testAscendModel(t, tr, m)
+		}
+	}
+}
+
+{{ if not .Unique -}}
+// random Add and Del of few keys compared with a multiset,
+// duplicates of a key can be at both sides of a node
+func Test{{ .TreeType }}_randomAdd(t *testing.T) {
+	var (
+		tr   = {{ .New }}()
+		m    = make(map[int]int) // key -> number of elements
+		size int
+	)
+	for n := 0; n < 10000; n++ {
+		var i = testKeyMin + rand.Intn(16)
+		if rand.Intn(2) == 0 {
+			if ok := tr.Add({{ testArgs "i" "i" }}); ok != (m[i] == 0) {
+				t.Fatal("Add: wrong ok", i, ok)
+			}
+			m[i], size = m[i]+1, size+1
+		} else if _, ok := tr.Del(testKey(i)); ok != (m[i] > 0) {
+			t.Fatal("Del: wrong ok", i, ok)
+		} else if ok {
+			m[i], size = m[i]-1, size-1
+		}
+		if tr.Size() != size {
+			t.Fatal("wrong size", tr.Size(), "want", size)
+		}
+		tr.testCheck(t)
+	}
+}
+
+{{ end -}}
+// compare ascending and descending iterations with the model
+func testAscendModel(t *testing.T, tr *{{ .TreeType }}, m map[int]int) {
+	var keys []int
+	for i := range m {
+		keys = append(keys, i)
+	}
+	sort.Ints(keys)
+	var called int
+	tr.Ascend(testBound(0), testBound(0), func({{ params }}) bool {
+		if called >= len(keys) {
+			t.Fatal("too many elements")
+		}
+		var i = keys[called]
+		if !testSameKey(k, testKey(i)) {
+			t.Fatal("wrong key", k, "want", testKey(i))
+		}
+{{- if .KeyValue }}
+		if !testSame(v, testValue(m[i])) {
+			t.Fatal("wrong v", v, "want", testValue(m[i]))
+		}
+{{- end }}
+		called++
+		return true
+	})
+	tr.Descend(testBound(0), testBound(0), func({{ params }}) bool {
+		if called == 0 {
+			t.Fatal("too many elements")
+		}
+		called--
+		if !testSameKey(k, testKey(keys[called])) {
+			t.Fatal("wrong key", k, "want", testKey(keys[called]))
+		}
+		return true
+	})
+	if called != 0 {
+		t.Fatal("wrong number of elements")
+	}
+}
+
+{{- if .ThreadSafe }}
+
+// concurrent changes and reads, use -race
+func Test{{ .TreeType }}_concurrent(t *testing.T) {
+	var (
+		tr   = {{ .New }}()
+		wg   sync.WaitGroup
+		errs = make(chan string, 8)
+	)
+	var report = func(err string) {
+		select {
+		case errs <- err:
+		default: // enough
+		}
+	}
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() { // writer
+			defer wg.Done()
+			for n := 0; n < 1000; n++ {
+				var i = testKeyMin + rand.Intn(testKeyMax)
+				if rand.Intn(2) == 0 {
+					tr.Ins({{ testArgs "i" "i" }})
+				} else {
+					tr.Del(testKey(i))
+				}
+			}
+		}()
+		go func() { // reader
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				tr.Get(testKey(testKeyMin + rand.Intn(testKeyMax)))
+				var prev {{ .Type }}
+				var called int
+				tr.Ascend(testBound(0), testBound(0), func({{ params }}) bool {
+					if called > 0 && !({{ testLess "prev" "k" }}) {
+						report(fmt.Sprint("wrong order ", prev, k))
+						return false
+					}
+					prev, called = k, called+1
+					return true
+				})
+				if called > testKeyMax {
+					report(fmt.Sprint("too many elements ", called))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	tr.testCheck(t)
+}
+{{- end }}
+
+{{ if .Generic -}}
+// random operations of the {{ .TreeType }}Func compared with the {{ .TreeType }}
+func Test{{ .TreeType }}Func_random(t *testing.T) {
+	var (
+		tr   = {{ .New }}()
+		fr   = {{ .New }}Func[K, V](cmp.Compare[K])
+		walk = func(each func(WalkFunc)) (s string) {
+			each(func({{ params }}) bool {
+				s += fmt.Sprint({{ args }}) + " "
+				return true
+			})
+			return
+		}
+	)
+	for n := 0; n < 10000; n++ {
+		var i, j = testKeyMin + rand.Intn(testKeyMax), rand.Intn(testKeyMax)
+		var v, fv V
+		var ok, fok bool
+		switch rand.Intn(5) {
+		case 0:
+			v, ok = tr.Ins({{ testArgs "i" "j" }})
+			fv, fok = fr.Ins({{ testArgs "i" "j" }})
+		case 1:
+			v, ok = tr.InsNx({{ testArgs "i" "j" }})
+			fv, fok = fr.InsNx({{ testArgs "i" "j" }})
+		case 2:
+			v, ok = tr.InsEx({{ testArgs "i" "j" }})
+			fv, fok = fr.InsEx({{ testArgs "i" "j" }})
+		case 3:
+			v, ok = tr.Del(testKey(i))
+			fv, fok = fr.Del(testKey(i))
+		case 4:
+{{- if .Unique }}
+			v, ok = tr.Get(testKey(i))
+			fv, fok = fr.Get(testKey(i))
+{{- else }}
+			ok, fok = tr.Add({{ testArgs "i" "j" }}), fr.Add({{ testArgs "i" "j" }})
+{{- end }}
+		}
+		if !testSame(v, fv) || ok != fok {
+			t.Fatal("different results", v, ok, "and", fv, fok)
+		}
+		if tr.Size() != fr.Size() {
+			t.Fatal("different sizes", tr.Size(), "and", fr.Size())
+		}
+		if w, fw := walk(tr.Walk), walk(fr.Walk); w != fw {
+			t.Fatal("different elements", w, "and", fw)
+		}
+	}
+}
+
+{{ end -}}
+func benchmarkFill() (tr *{{ .TreeType }}) {
+	return testFill(testRange(testKeyMin, testKeyMax, true))
+}
+
+func Benchmark{{ .TreeType }}_Ins(b *testing.B) {
+	var tr = {{ .New }}()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var i = testKeyMin + n%testKeyMax
+		tr.Ins({{ testArgs "i" "i" }})
+	}
+}
+
+func Benchmark{{ .TreeType }}_Get(b *testing.B) {
+	var tr = benchmarkFill()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Get(testKey(testKeyMin + n%testKeyMax))
+	}
+}
+
+// delete and insert back
+func Benchmark{{ .TreeType }}_Del(b *testing.B) {
+	var tr = benchmarkFill()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var i = testKeyMin + n%testKeyMax
+		tr.Del(testKey(i))
+		tr.Ins({{ testArgs "i" "i" }})
+	}
+}
+
+func Benchmark{{ .TreeType }}_Ascend(b *testing.B) {
+	var tr = benchmarkFill()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Ascend(testBound(0), testBound(0), func({{ params }}) bool {
+			return true
+		})
+	}
+}
+
+{{ if .Generic }}
+// the {{ .TreeType }}Func compares keys using function call,
+// compare with the Benchmark{{ .TreeType }}_Get
+func Benchmark{{ .TreeType }}Func_Get(b *testing.B) {
+	var tr = {{ .New }}Func[K, V](cmp.Compare[K])
+	for _, i := range testRange(testKeyMin, testKeyMax, true) {
+		tr.Ins({{ testArgs "i" "i" }})
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Get(testKey(testKeyMin + n%testKeyMax))
+	}
+}
+{{ end }}
+{{ template "extraTests" . }}
+
+{{- define "extraTests" }}{{ end }}
+`
@@ -0,0 +1,294 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// a typeRef is parsed type name like 'int', 'Name',
+// '*Name', 'time.Time' or '*github.com/user/pkg.Name'
+type typeRef struct {
+	ptr  bool   // pointer to the type
+	path string // import path, empty for local and builtin types
+	name string // name of the type
+}
+
+// parse given type name, it returns false if
+// the name is not a reference to a named type
+func parseTypeRef(s string) (ref typeRef, ok bool) {
+	if strings.HasPrefix(s, "*") {
+		ref.ptr, s = true, s[1:]
+	}
+	if i := strings.LastIndexByte(s, '.'); i >= 0 {
+		ref.path, s = s[:i], s[i+1:]
+		if ref.path == "" || strings.ContainsAny(ref.path, " \t[]()*{},") {
+			return
+		}
+	}
+	ref.name = s
+	ok = token.IsIdentifier(s)
+	return
+}
+
+// a typeResolver looks up named types using sources
+// of packages
+type typeResolver struct {
+	fset  *token.FileSet
+	imp   types.ImporterFrom
+	dir   string         // directory of the target package
+	skip  string         // file of the target package to skip
+	local *types.Package // loaded target package
+}
+
+func newTypeResolver(dir, skip string) (tr *typeResolver) {
+	tr = new(typeResolver)
+	tr.fset = token.NewFileSet()
+	tr.imp = importer.ForCompiler(tr.fset, "source", nil).(types.ImporterFrom)
+	tr.dir, tr.skip = dir, skip
+	return
+}
+
+// load and type check package of the target directory,
+// errors are ignored, because the package can use the
+// structure that is not generated yet
+func (tr *typeResolver) localPackage() (pkg *types.Package, err error) {
+	if tr.local != nil {
+		return tr.local, nil
+	}
+	var filter = func(fi os.FileInfo) bool {
+		var name = fi.Name()
+		return !strings.HasSuffix(name, "_test.go") &&
+			filepath.Join(tr.dir, name) != tr.skip
+	}
+	var pkgs map[string]*ast.Package
+	pkgs, err = parser.ParseDir(tr.fset, tr.dir, filter, 0)
+	if err != nil {
+		return
+	}
+	var files []*ast.File
+	for _, p := range pkgs {
+		for _, file := range p.Files {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", tr.dir)
+	}
+	var conf = types.Config{
+		Importer: tr.imp,
+		Error:    func(error) {}, // ignore
+	}
+	tr.local, _ = conf.Check(files[0].Name.Name, tr.fset, files, nil)
+	return tr.local, nil
+}
+
+// lookup given type returning the type and its package,
+// the package is nil for builtin types
+func (tr *typeResolver) lookup(ref typeRef) (typ types.Type,
+	pkg *types.Package, err error) {
+
+	var scope *types.Scope
+	switch {
+	case ref.path != "":
+		if pkg, err = tr.imp.ImportFrom(ref.path, tr.dir, 0); err != nil {
+			return
+		}
+		scope = pkg.Scope()
+	case types.Universe.Lookup(ref.name) != nil:
+		scope = types.Universe
+	default:
+		if pkg, err = tr.localPackage(); err != nil {
+			return
+		}
+		scope = pkg.Scope()
+	}
+
+	var tn, ok = scope.Lookup(ref.name).(*types.TypeName)
+	if !ok {
+		if pkg == nil {
+			return nil, nil, fmt.Errorf("%s is not a type", ref.name)
+		}
+		return nil, nil, fmt.Errorf("no type %s in package %s",
+			ref.name, pkg.Path())
+	}
+	if typ = tn.Type(); ref.ptr {
+		typ = types.NewPointer(typ)
+	}
+	return
+}
+
+// find method with given name, single argument of the typ
+// and single result of given kind
+func findMethod(typ types.Type, name string, result types.BasicKind) bool {
+	var mset *types.MethodSet
+	if _, isPtr := typ.(*types.Pointer); isPtr || types.IsInterface(typ) {
+		mset = types.NewMethodSet(typ)
+	} else {
+		// keys of a tree are addressable
+		mset = types.NewMethodSet(types.NewPointer(typ))
+	}
+	var sel = mset.Lookup(nil, name) // exported, no package required
+	if sel == nil {
+		return false
+	}
+	var sig, ok = sel.Type().(*types.Signature)
+	if !ok || sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	if !types.Identical(sig.Params().At(0).Type(), typ) {
+		return false
+	}
+	var basic, isBasic = sig.Results().At(0).Type().Underlying().(*types.Basic)
+	return isBasic && basic.Kind() == result
+}
+
+// comparison returns less and equal formats for given type
+func comparison(typ types.Type) (less, equal string, err error) {
+	if basic, ok := typ.Underlying().(*types.Basic); ok &&
+		basic.Info()&types.IsOrdered != 0 {
+		return "%s < %s", "%s == %s", nil // numbers and strings
+	}
+	var hasLess = findMethod(typ, "Less", types.Bool)
+	switch {
+	case hasLess && findMethod(typ, "Equal", types.Bool):
+		return "%s.Less(%s)", "%s.Equal(%s)", nil
+	case findMethod(typ, "Compare", types.Int):
+		return "%s.Compare(%s) < 0", "%s.Compare(%s) == 0", nil
+	case hasLess:
+		// keys are equal if neither is less, the == compares
+		// pointers or fields the Less can ignore
+		return "%[1]s.Less(%[2]s)", "!%[1]s.Less(%[2]s) && !%[2]s.Less(%[1]s)", nil
+	}
+	return "", "", fmt.Errorf("type %[1]s can't be ordered: it's not a number "+
+		"or string and has no 'Less(%[1]s) bool' or 'Compare(%[1]s) int' "+
+		"method; use -less and -equal formats", typ)
+}
+
+// resolve given type name returning the name for the generated
+// code and the type; it adds required import to the tree
+func (r *rbTree) resolveType(tr *typeResolver, name string,
+	ordered bool) (res string, typ types.Type, err error) {
+
+	var ref, ok = parseTypeRef(name)
+	if !ok {
+		if ordered {
+			return "", nil, fmt.Errorf("can't resolve type %s; use -less "+
+				"and -equal formats", name)
+		}
+		return name, nil, nil // leave as is
+	}
+
+	var pkg *types.Package
+	if typ, pkg, err = tr.lookup(ref); err != nil {
+		return "", nil, fmt.Errorf("resolving type %s: %v", name, err)
+	}
+
+	if res = ref.name; ref.path != "" {
+		res = pkg.Name() + "." + ref.name
+		if !r.Imports.Contain(pkg.Path()) {
+			r.Imports = append(r.Imports, pkg.Path())
+		}
+	}
+	if ref.ptr {
+		res = "*" + res
+	}
+
+	if !ordered || (r.Less != "" && r.Equal != "") {
+		return
+	}
+	var less, equal string
+	if less, equal, err = comparison(typ); err != nil {
+		return
+	}
+	if r.Less == "" {
+		r.Less = less
+	}
+	if r.Equal == "" {
+		r.Equal = equal
+	}
+	return
+}
+
+// resolve key and value types, their imports and comparison
+// formats of the keys using sources of packages
+func (r *rbTree) resolve() (err error) {
+	var (
+		needOrder = r.Less == "" || r.Equal == ""
+		needTests = r.Tests && (r.TestKey == "" ||
+			r.Value != "" && r.TestValue == "")
+	)
+	if r.Generic {
+		return // type parameters
+	}
+	if !needOrder && !needTests && !isQualified(r.Type) &&
+		!isQualified(r.Value) {
+		return // nothing to resolve
+	}
+	var tr = r.resolver()
+	r.Type, r.keyType, err = r.resolveType(tr, r.Type, needOrder)
+	if err != nil {
+		return optionErr("type", err)
+	}
+	if r.Value == "" {
+		return
+	}
+	if r.Value, r.valueType, err = r.resolveType(tr, r.Value, false); err != nil {
+		return optionErr("value", err)
+	}
+	return
+}
+
+// directory of the target package
+func (r *rbTree) dir() string {
+	if r.Output == "" {
+		return "."
+	}
+	return filepath.Dir(r.Output)
+}
+
+// resolver of types of the target package, the verify
+// reuses packages loaded by the resolve
+func (r *rbTree) resolver() *typeResolver {
+	if r.tr == nil {
+		r.tr = newTypeResolver(r.dir(), r.Output)
+	}
+	return r.tr
+}
+
+// is given type name contains package
+func isQualified(name string) bool {
+	var ref, ok = parseTypeRef(name)
+	return ok && ref.path != ""
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveType(t *testing.T) {
+	var dir = t.TempDir()
+	const pkg = `package mypkg
+
+type Key struct{ A, B int } // ordered by the A only
+
+func (k Key) Less(o Key) bool { return k.A < o.A }
+
+type PKey struct{ A int }
+
+func (k *PKey) Less(o *PKey) bool { return k.A < o.A }
+
+type EKey struct{ A int }
+
+func (k EKey) Less(o EKey) bool  { return k.A < o.A }
+func (k EKey) Equal(o EKey) bool { return k.A == o.A }
+
+type CKey struct{ A int }
+
+func (k CKey) Compare(o CKey) int { return k.A - o.A }
+
+type NKey struct{}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "pkg.go"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var tr = newTypeResolver(dir, "")
+	for _, tt := range []struct {
+		name, less, equal string
+	}{
+		{"int", "%s < %s", "%s == %s"},
+		{"Key", "%[1]s.Less(%[2]s)", "!%[1]s.Less(%[2]s) && !%[2]s.Less(%[1]s)"},
+		{"*PKey", "%[1]s.Less(%[2]s)", "!%[1]s.Less(%[2]s) && !%[2]s.Less(%[1]s)"},
+		{"EKey", "%s.Less(%s)", "%s.Equal(%s)"},
+		{"CKey", "%s.Compare(%s) < 0", "%s.Compare(%s) == 0"},
+	} {
+		var r = new(rbTree)
+		var res, _, err = r.resolveType(tr, tt.name, true)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if res != tt.name || r.Less != tt.less || r.Equal != tt.equal {
+			t.Errorf("%s: got %s, %q, %q, want %q, %q", tt.name, res,
+				r.Less, r.Equal, tt.less, tt.equal)
+		}
+	}
+	if _, _, err := new(rbTree).resolveType(tr, "NKey", true); err == nil {
+		t.Error("missing error of unordered type")
+	}
+}
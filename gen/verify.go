@@ -0,0 +1,302 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// a typeError is an error of generated code found by the verify
+type typeError struct {
+	err  types.Error // the first error
+	line string      // source line of the error
+	test bool        // error of the tests
+}
+
+// Error implements error interface
+func (t *typeError) Error() string {
+	return "generated code doesn't compile: " + t.err.Error()
+}
+
+// name of generated file in errors
+func fileName(path string) string {
+	if path == "" {
+		return "<stdout>"
+	}
+	return path
+}
+
+// verify type checks generated files with files of the target
+// package, it returns the first error of the generated files
+func (r *rbTree) verify(fs []File) (err error) {
+	var (
+		tr    = r.resolver()
+		srcs  = make(map[string][]byte) // generated files by name
+		files = r.packageFiles(fs)
+	)
+	// generated files are the last, thus a declaration of the
+	// package conflicting with the tree is reported by them
+	for _, f := range fs {
+		var name = fileName(f.Path)
+		var file *ast.File
+		if file, err = parser.ParseFile(tr.fset, name, f.Src, 0); err != nil {
+			return
+		}
+		files, srcs[name] = append(files, file), f.Src
+	}
+	var first *typeError
+	var conf = types.Config{
+		Importer: tr.imp,
+		Error: func(err error) {
+			var te, ok = err.(types.Error)
+			if !ok || first != nil || r.ignore(te) {
+				return
+			}
+			var pos = te.Fset.Position(te.Pos)
+			if src, ok := srcs[pos.Filename]; ok {
+				first = &typeError{
+					err:  te,
+					line: sourceLine(src, pos.Line),
+					test: strings.HasSuffix(pos.Filename, "_test.go"),
+				}
+			}
+		},
+	}
+	conf.Check(r.Package, tr.fset, files, nil)
+	if first != nil {
+		return first
+	}
+	return nil
+}
+
+// parse files of the target package except files the generator
+// produces; broken files and errors of the files are ignored,
+// because the package can use the structure not generated yet
+func (r *rbTree) packageFiles(fs []File) (files []*ast.File) {
+	var skip = make(map[string]bool)
+	for _, f := range fs {
+		skip[filepath.Clean(f.Path)] = true
+	}
+	var header = fileHeader(fs[0].Src)
+	var filter = func(fi os.FileInfo) bool {
+		var path = filepath.Join(r.dir(), fi.Name())
+		if skip[path] {
+			return false
+		}
+		// previous version of the tree generated to stdout
+		var src, err = ioutil.ReadFile(path)
+		return err != nil || !bytes.HasPrefix(src, header)
+	}
+	var pkgs, _ = parser.ParseDir(r.resolver().fset, r.dir(), filter, 0)
+	var pkg, ok = pkgs[r.Package]
+	if !ok {
+		return // new package
+	}
+	var names = make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names) // stable order of errors
+	for _, name := range names {
+		files = append(files, pkg.Files[name])
+	}
+	return
+}
+
+// the generated and the command lines of a generated file
+func fileHeader(src []byte) []byte {
+	var end = 0
+	for i := 0; i < 2; i++ {
+		var n = bytes.IndexByte(src[end:], '\n')
+		if n < 0 {
+			return src
+		}
+		end += n + 1
+	}
+	return src[:end]
+}
+
+// import path of an error like 'could not import path (reason)'
+func importError(te types.Error) (path string, ok bool) {
+	const prefix = "could not import "
+	if !strings.HasPrefix(te.Msg, prefix) {
+		return
+	}
+	path = strings.TrimPrefix(te.Msg, prefix)
+	if i := strings.IndexByte(path, ' '); i >= 0 {
+		path = path[:i]
+	}
+	return path, true
+}
+
+// ignore packages can't be imported, except the -import ones;
+// a module can require them later
+func (r *rbTree) ignore(te types.Error) bool {
+	var path, ok = importError(te)
+	return ok && !r.given.Imports.Contain(path)
+}
+
+// line of given source, the n starts from 1
+func sourceLine(src []byte, n int) string {
+	var lines = bytes.Split(src, []byte("\n"))
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	return string(lines[n-1])
+}
+
+// verbs of a format
+var verbRegexp = regexp.MustCompile(`%(\[\d+\])?s`)
+
+// is the column of the line inside code produced by given format
+func covers(format, line string, column int) bool {
+	var parts = verbRegexp.Split(format, -1)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	var re, err = regexp.Compile(strings.Join(parts, `(.+?)`))
+	if err != nil {
+		return false
+	}
+	for _, m := range re.FindAllStringIndex(line, -1) {
+		if m[0] < column && column <= m[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// name of type of given option without pointer and package
+func typeName(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	return typ[strings.LastIndexByte(typ, '.')+1:]
+}
+
+// blame the option or the block of the -templates producing
+// code of the error of the verify
+func (r *rbTree) blame(text string, tmpl *template.Template,
+	err error) error {
+
+	var te, ok = err.(*typeError)
+	if !ok {
+		return err
+	}
+	var (
+		g        = r.given
+		col      = te.err.Fset.Position(te.err.Pos).Column
+		in       = func(format string) bool { return covers(format, te.line, col) }
+		path, im = importError(te.err)
+	)
+	switch {
+	case strings.Contains(te.err.Msg, "redeclared in this block"):
+		return optionErr("tree", fmt.Errorf("%v; use -tree or -prefix "+
+			"to name the tree", te))
+	case te.test && g.TestKey != "" && in(g.TestKey):
+		return optionErr("test-key", te)
+	case te.test && g.TestValue != "" && in(g.TestValue):
+		return optionErr("test-value", te)
+	case g.Less != "" && in(g.Less):
+		return optionErr("less", te)
+	case g.Equal != "" && in(g.Equal):
+		return optionErr("equal", te)
+	case g.Comparable && (in("%s < %s") || in("%s == %s")):
+		return optionErr("comparable", te)
+	case te.err.Msg == "undefined: "+typeName(g.Type):
+		return optionErr("type", te)
+	case g.Value != "" && te.err.Msg == "undefined: "+typeName(g.Value):
+		return optionErr("value", te)
+	case im && strings.Contains(te.line, strconv.Quote(path)):
+		return optionErr("import", te)
+	case g.Templates != "":
+		if block, ok := r.blameTemplates(text, tmpl); ok {
+			if block == "" {
+				return optionErr("templates", te)
+			}
+			return optionErr("templates", fmt.Errorf("block %q: %v", block, te))
+		}
+	}
+	return te
+}
+
+// find block of the -templates producing broken code; it returns
+// empty block if only many blocks together break the code, and
+// false if the built-in templates are broken too
+func (r *rbTree) blameTemplates(text string,
+	tmpl *template.Template) (block string, ok bool) {
+
+	var base, err = parse(r.Structure, text, r.funcs(), "")
+	if err != nil {
+		return
+	}
+	var check = func(tmpl *template.Template) bool {
+		var fs, err = r.execute(tmpl)
+		return err == nil && r.verify(fs) == nil
+	}
+	if !check(base) {
+		return // not the -templates
+	}
+	var names []string // overridden blocks
+	for _, t := range tmpl.Templates() {
+		var b = base.Lookup(t.Name())
+		if b != nil && t.Tree != nil && b.Tree != nil &&
+			t.Tree.Root.String() != b.Tree.Root.String() {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	// built-in templates with all overridden blocks except one,
+	// an empty built-in block can't replace overridden one
+	for _, name := range names {
+		var fixed *template.Template
+		if fixed, err = base.Lookup(r.Structure).Clone(); err != nil {
+			return
+		}
+		for _, other := range names {
+			if other == name {
+				continue
+			}
+			if _, err = fixed.AddParseTree(other, tmpl.Lookup(other).Tree); err != nil {
+				return
+			}
+		}
+		if check(fixed) {
+			return name, true
+		}
+	}
+	return "", true
+}
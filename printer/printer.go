@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+// Package printer implements dependency-free printer of trees.
+// Trees of the gods print themselves using the Printer interface,
+// and the Tree is built-in Printer rendering ASCII.
+package printer
+
+import (
+	"strings"
+)
+
+// A Printer is a node of printed tree. The Add adds child
+// node with given label returning the child.
+type Printer interface {
+	Add(label string) Printer
+}
+
+// A Tree is a Printer rendering tree using ASCII, like
+//
+//	rb-tree
+//	`-- [B] 2
+//	    |-- [R] 1
+//	    `-- [R] 3
+type Tree struct {
+	label    string
+	children []*Tree
+}
+
+// New creates Tree with given label of the root.
+func New(label string) (t *Tree) {
+	t = new(Tree)
+	t.label = label
+	return
+}
+
+// Add implements Printer interface.
+func (t *Tree) Add(label string) Printer {
+	var c = New(label)
+	t.children = append(t.children, c)
+	return c
+}
+
+// String renders the tree.
+func (t *Tree) String() string {
+	var sb strings.Builder
+	sb.WriteString(t.label)
+	sb.WriteByte('\n')
+	t.render(&sb, "")
+	return sb.String()
+}
+
+// render children of the t, the indent is prefix of their lines
+func (t *Tree) render(sb *strings.Builder, indent string) {
+	for i, c := range t.children {
+		var branch, next = "|-- ", "|   "
+		if i == len(t.children)-1 {
+			branch, next = "`-- ", "    " // last child
+		}
+		sb.WriteString(indent)
+		sb.WriteString(branch)
+		sb.WriteString(c.label)
+		sb.WriteByte('\n')
+		c.render(sb, indent+next)
+	}
+}
@@ -23,8 +23,29 @@
 //  0. You just DO WHAT THE FUCK YOU WANT TO.
 //
 
-package main
+package printer
 
-func genAVLTree(args []string) {
-	//
+import (
+	"testing"
+)
+
+func TestTree_String(t *testing.T) {
+	var tr = New("tree")
+	if got := tr.String(); got != "tree\n" {
+		t.Errorf("empty tree: %q", got)
+	}
+	var a = tr.Add("a")
+	a.Add("b").Add("c")
+	a.Add("d")
+	tr.Add("e").Add("f")
+	const want = "tree\n" +
+		"|-- a\n" +
+		"|   |-- b\n" +
+		"|   |   `-- c\n" +
+		"|   `-- d\n" +
+		"`-- e\n" +
+		"    `-- f\n"
+	if got := tr.String(); got != want {
+		t.Errorf("wrong tree\n%s\nwant\n%s", got, want)
+	}
 }
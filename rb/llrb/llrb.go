@@ -0,0 +1,568 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+// Package llrb implements Sedgewick's left-leaning red-black tree.
+// A red link of the tree always leans left, that makes code of the
+// tree shorter. The tree has the same API as the rb.Tree.
+package llrb
+
+import (
+	"fmt"
+
+	"github.com/logrusorgru/gods/bound"
+	"github.com/logrusorgru/gods/printer"
+)
+
+type color bool
+
+const (
+	red   color = true
+	black color = false
+)
+
+type LessFunc func(a, b interface{}) bool
+
+type EqualFunc func(a, b interface{}) bool
+
+type ZeroFunc func(a interface{}) bool
+
+// Bound of a range of the AscendRange or the DescendRange, use
+// bound.Inclusive, bound.Exclusive or bound.Unbounded.
+type Bound = bound.Bound
+
+type node struct {
+	l, r *node
+	c    color
+	k    interface{}
+	v    interface{}
+}
+
+func newNode(k, v interface{}) (n *node) {
+	n = new(node)
+	n.c = red
+	n.k = k
+	n.v = v
+	return
+}
+
+func (n *node) isRed() bool {
+	return n != nil && n.c == red
+}
+
+// the n becomes right child of its left child
+func (n *node) rotateRight() (x *node) {
+	x = n.l
+	n.l, x.r = x.r, n
+	x.c, n.c = n.c, red
+	return
+}
+
+// the n becomes left child of its right child
+func (n *node) rotateLeft() (x *node) {
+	x = n.r
+	n.r, x.l = x.l, n
+	x.c, n.c = n.c, red
+	return
+}
+
+// flip colors of the n and its children
+func (n *node) flipColors() {
+	n.c = !n.c
+	n.l.c = !n.l.c
+	n.r.c = !n.r.c
+}
+
+// restore left-leaning invariants on the way up
+func (n *node) fixUp() *node {
+	if n.r.isRed() && !n.l.isRed() {
+		n = n.rotateLeft()
+	}
+	if n.l.isRed() && n.l.l.isRed() {
+		n = n.rotateRight()
+	}
+	if n.l.isRed() && n.r.isRed() {
+		n.flipColors()
+	}
+	return n
+}
+
+// the n is red, make the n.l or one of its children red
+func (n *node) moveRedLeft() *node {
+	n.flipColors()
+	if n.r.l.isRed() {
+		n.r = n.r.rotateRight()
+		n = n.rotateLeft()
+		n.flipColors()
+	}
+	return n
+}
+
+// the n is red, make the n.r or one of its children red
+func (n *node) moveRedRight() *node {
+	n.flipColors()
+	if n.l.l.isRed() {
+		n = n.rotateRight()
+		n.flipColors()
+	}
+	return n
+}
+
+// delete minimal node of the subtree returning
+// new root of the subtree and the deleted node
+func (n *node) deleteMin() (*node, *node) {
+	if n.l == nil {
+		return nil, n
+	}
+	if !n.l.isRed() && !n.l.l.isRed() {
+		n = n.moveRedLeft()
+	}
+	var min *node
+	n.l, min = n.l.deleteMin()
+	return n.fixUp(), min
+}
+
+type Tree struct {
+	r *node
+
+	less  LessFunc
+	equal EqualFunc
+	zero  ZeroFunc
+
+	size int
+}
+
+func New(less LessFunc, equal EqualFunc, zero ZeroFunc) (t *Tree) {
+	t = new(Tree)
+	t.less = less
+	t.equal = equal
+	t.zero = zero
+	return
+}
+
+// findNode by key
+func (t *Tree) findNode(k interface{}) (n *node) {
+	var (
+		less  = t.less
+		equal = t.equal
+	)
+	for n = t.r; n != nil; {
+		switch {
+		case equal(k, n.k):
+			return
+		case less(k, n.k):
+			n = n.l
+		default:
+			n = n.r
+		}
+	}
+	return
+}
+
+// insert given node to subtree of the h returning
+// new root of the subtree
+func (t *Tree) insert(h, n *node) *node {
+	if h == nil {
+		return n
+	}
+	if t.less(n.k, h.k) {
+		h.l = t.insert(h.l, n) // left (less)
+	} else {
+		h.r = t.insert(h.r, n) // right (greater or equal)
+	}
+	return h.fixUp()
+}
+
+// insert node to the tree
+func (t *Tree) insertNode(n *node) {
+	t.size++
+	t.r = t.insert(t.r, n)
+	t.r.c = black // root must be black
+}
+
+// Ins is insert or overwrite, returning
+//
+//     1. previous value, false
+//     2. nil, true
+//
+// The first case where an existing value overwritten. The
+// second case where created new item.
+func (t *Tree) Ins(k, v interface{}) (p interface{}, ok bool) {
+	var n = t.findNode(k)
+	if n != nil {
+		p, n.v = n.v, v
+		return // p, false
+	}
+	// n is nil
+	t.insertNode(newNode(k, v))
+	return nil, true
+}
+
+// InsNx is insert if does not exist, returning
+//
+//     1. existing value, false
+//     2. nil, true
+//
+// The first case if item already exists. The second case
+// if item created.
+func (t *Tree) InsNx(k, v interface{}) (e interface{}, ok bool) {
+	var n = t.findNode(k)
+	if n != nil {
+		return n.v, false // already exists
+	}
+	// n is nil
+	t.insertNode(newNode(k, v))
+	return nil, true
+}
+
+// InsEx is insert if exists, returning
+//
+//     1. previous value, true
+//     2. nil, false
+//
+// The first case if item already exists and has been overwritten.
+// The second case if item doesn't exist.
+func (t *Tree) InsEx(k, v interface{}) (p interface{}, ok bool) {
+	var n = t.findNode(k)
+	if n == nil {
+		return nil, false // does not exist
+	}
+	p, n.v, ok = n.v, v, true
+	return
+}
+
+// Add is add new node even if it already exists. The Add called
+// with the same key many times makes the Tree not unique. The
+// Add returns true if item with given key is first in the Tree,
+// i.e. if the Tree is still unique.
+func (t *Tree) Add(k, v interface{}) (ok bool) {
+	ok = t.findNode(k) == nil // the Tree is or becomes not unique
+	t.insertNode(newNode(k, v))
+	return
+}
+
+// delete node with given key from subtree of the h returning
+// new root of the subtree and value of the deleted node; the
+// subtree must contain the key
+func (t *Tree) delete(h *node, k interface{}) (_ *node, v interface{}) {
+	if t.less(k, h.k) {
+		if !h.l.isRed() && !h.l.l.isRed() {
+			h = h.moveRedLeft()
+		}
+		h.l, v = t.delete(h.l, k)
+		return h.fixUp(), v
+	}
+	if h.l.isRed() {
+		h = h.rotateRight()
+	}
+	if t.equal(k, h.k) && h.r == nil {
+		return nil, h.v // leaf
+	}
+	var x = h
+	if !h.r.isRed() && !h.r.l.isRed() {
+		h = h.moveRedRight()
+	}
+	// the h rotated by the moveRedRight can be a duplicate from
+	// the left, delete the x that is at the top of the h.r then
+	if h == x && t.equal(k, h.k) {
+		var min *node
+		v = h.v
+		h.r, min = h.r.deleteMin()
+		h.k, h.v = min.k, min.v // replace with successor
+	} else {
+		h.r, v = t.delete(h.r, k)
+	}
+	return h.fixUp(), v
+}
+
+// Get value by key. It returns (nil, false) if the
+// Tree doesn't contain element with given key. If
+// the Tree is not unique, the Get return first
+// element. Use the Ascend or the Descend to get all
+// non-unique elements.
+func (t *Tree) Get(k interface{}) (v interface{}, ok bool) {
+	var n = t.findNode(k)
+	if n != nil {
+		return n.v, true // got it
+	}
+	return nil, false // not found
+}
+
+// Del deletes element by key. It returns deleted value and true,
+// or (nil, false) if the Tree doesn't contain element with given
+// key. If the Tree is not unique, the Del deletes one of elements.
+func (t *Tree) Del(k interface{}) (v interface{}, ok bool) {
+	if t.findNode(k) == nil {
+		return nil, false // does not exist
+	}
+	if !t.r.l.isRed() && !t.r.r.isRed() {
+		t.r.c = red
+	}
+	t.r, v = t.delete(t.r, k)
+	if t.r != nil {
+		t.r.c = black // root must be black
+	}
+	t.size-- // reduce
+	return v, true
+}
+
+func (t *Tree) minNode() (st []*node, n *node) {
+	if t.r == nil {
+		return
+	}
+	for n = t.r; n.l != nil; n = n.l {
+		st = append(st, n)
+	}
+	return
+}
+
+func (t *Tree) maxNode() (st []*node, n *node) {
+	if t.r == nil {
+		return
+	}
+	for n = t.r; n.r != nil; n = n.r {
+		st = append(st, n)
+	}
+	return
+}
+
+func (t *Tree) Min() (k, v interface{}, ok bool) {
+	if _, n := t.minNode(); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+func (t *Tree) Max() (k, v interface{}, ok bool) {
+	if _, n := t.maxNode(); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+func (t *Tree) Size() int {
+	return t.size
+}
+
+func (t *Tree) Clear() {
+	t.size, t.r = 0, nil
+}
+
+// A WalkFunc is iterator. If it
+// returns false iteration stops.
+type WalkFunc func(k, v interface{}) (next bool)
+
+func pop(ns []*node) (xs []*node, n *node) {
+	if len(ns) == 0 {
+		return ns, nil
+	}
+	n, xs = ns[len(ns)-1], ns[:len(ns)-1]
+	return
+}
+
+func walk(n *node, walkFunc WalkFunc) bool {
+	if n == nil {
+		return true
+	}
+	return walkFunc(n.k, n.v) && walk(n.l, walkFunc) && walk(n.r, walkFunc)
+}
+
+// Walk elements of the Tree without any order.
+func (t *Tree) Walk(walkFunc WalkFunc) {
+	walk(t.r, walkFunc) // recursive
+}
+
+// (-inf, +inf)
+func (t *Tree) ascend(ascendFunc WalkFunc) {
+	for st, n := t.minNode(); n != nil; {
+		if !ascendFunc(n.k, n.v) {
+			return
+		}
+		if n.r != nil {
+			n = n.r
+			for n.l != nil {
+				st = append(st, n)
+				n = n.l
+			}
+		} else {
+			st, n = pop(st)
+		}
+	}
+}
+
+// the first node of a range of the lower bound and
+// its ancestors greater than the node
+func (t *Tree) lowerNode(lo Bound) (st []*node, n *node) {
+	for x, less := t.r, t.less; x != nil; {
+		if lo.Below(less, x.k) {
+			x = x.r
+		} else {
+			st, x = append(st, x), x.l
+		}
+	}
+	st, n = pop(st)
+	return
+}
+
+// the zero key is unbounded, other keys are inclusive
+func (t *Tree) zeroBound(k interface{}) Bound {
+	if t.zero(k) {
+		return bound.Unbounded
+	}
+	return bound.Inclusive(k)
+}
+
+// AscendRange iterates elements of the Tree in ascending order
+// from the lower bound from to the upper bound to. Unlike the
+// Ascend, a bound can be any key, including zero one.
+func (t *Tree) AscendRange(from, to Bound, ascendFunc WalkFunc) {
+	var (
+		st, n = t.lowerNode(from)
+		less  = t.less
+	)
+	for n != nil {
+		if to.Above(less, n.k) {
+			return // that's all
+		}
+		if !ascendFunc(n.k, n.v) {
+			return
+		}
+		if n.r != nil {
+			n = n.r
+			for n.l != nil {
+				st = append(st, n)
+				n = n.l
+			}
+		} else {
+			st, n = pop(st)
+		}
+	}
+}
+
+// Ascend iterates elements of the tree ascending order. The ZeroFunc
+// used to determine ascending range: a zero from or to is unbounded,
+// other keys are inclusive bounds. See also the AscendRange.
+func (t *Tree) Ascend(from, to interface{}, ascendFunc WalkFunc) {
+	t.AscendRange(t.zeroBound(from), t.zeroBound(to), ascendFunc)
+}
+
+// (-inf, +inf) (reversed)
+func (t *Tree) descend(descendFunc WalkFunc) {
+	for st, n := t.maxNode(); n != nil; {
+		if !descendFunc(n.k, n.v) {
+			return
+		}
+		if n.l != nil {
+			n = n.l
+			for n.r != nil {
+				st = append(st, n)
+				n = n.r
+			}
+		} else {
+			st, n = pop(st)
+		}
+	}
+}
+
+// the last node of a range of the upper bound and
+// its ancestors less than the node
+func (t *Tree) upperNode(hi Bound) (st []*node, n *node) {
+	for x, less := t.r, t.less; x != nil; {
+		if hi.Above(less, x.k) {
+			x = x.l
+		} else {
+			st, x = append(st, x), x.r
+		}
+	}
+	st, n = pop(st)
+	return
+}
+
+// DescendRange iterates elements of the Tree in descending order
+// from the upper bound from to the lower bound to. Unlike the
+// Descend, a bound can be any key, including zero one.
+func (t *Tree) DescendRange(from, to Bound, descendFunc WalkFunc) {
+	var (
+		st, n = t.upperNode(from)
+		less  = t.less
+	)
+	for n != nil {
+		if to.Below(less, n.k) {
+			return // that's all
+		}
+		if !descendFunc(n.k, n.v) {
+			return
+		}
+		if n.l != nil {
+			n = n.l
+			for n.r != nil {
+				st = append(st, n)
+				n = n.r
+			}
+		} else {
+			st, n = pop(st)
+		}
+	}
+}
+
+// Descend iterates elements of the tree descending order. The
+// ZeroFunc used to determine descending range like the Ascend
+// does. See also the DescendRange.
+func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
+	t.DescendRange(t.zeroBound(from), t.zeroBound(to), descendFunc)
+}
+
+// Printer prints a Tree, use printer.New
+// to print to string
+type Printer = printer.Printer
+
+func (c color) String() string {
+	if c == red {
+		return "R"
+	}
+	return "B"
+}
+
+// print subtree of the n
+func (n *node) print(p Printer) {
+	p = p.Add(fmt.Sprintf("[%s] %v", n.c, n.k))
+	if n.l == nil && n.r == nil {
+		return
+	}
+	for _, c := range []*node{n.l, n.r} {
+		if c == nil {
+			p.Add("nil") // keep left and right
+			continue
+		}
+		c.print(p)
+	}
+}
+
+// Print the Tree to given Printer. Labels of nodes
+// are colours and keys, like "[R] 5".
+func (t *Tree) Print(p Printer) {
+	if t.r != nil {
+		t.r.print(p)
+	}
+}
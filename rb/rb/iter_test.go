@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+//go:build go1.23
+
+package rb
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/logrusorgru/gods/bound"
+)
+
+func TestTree_All(t *testing.T) {
+	// All() iter.Seq2[interface{}, interface{}]
+	for _, r := range Ranges {
+		tr := newNatiral()
+		for _, i := range r {
+			tr.Ins(i, i)
+		}
+		var i = keyMin
+		for k, v := range tr.All() {
+			if k != i || v != i {
+				t.Fatal("wrong element", k, v, i, rs(r))
+			}
+			i++
+		}
+		if i != keyMax+1 {
+			t.Fatal("wrong end", i, rs(r))
+		}
+		for k := range tr.All() {
+			if k != keyMin {
+				t.Fatal("wrong first", k)
+			}
+			break
+		}
+		if m := maps.Collect(tr.All()); len(m) != tr.Size() {
+			t.Error("wrong collected", len(m), tr.Size())
+		}
+	}
+}
+
+func TestTree_Backward(t *testing.T) {
+	// Backward() iter.Seq2[interface{}, interface{}]
+	for _, r := range Ranges {
+		tr := newNatiral()
+		for _, i := range r {
+			tr.Ins(i, i)
+		}
+		var i = keyMax
+		for k, v := range tr.Backward() {
+			if k != i || v != i {
+				t.Fatal("wrong element", k, v, i, rs(r))
+			}
+			if i--; i < keyMax-10 {
+				break
+			}
+		}
+		if i != keyMax-11 {
+			t.Fatal("wrong end", i, rs(r))
+		}
+	}
+}
+
+func TestTree_Range(t *testing.T) {
+	// Range(from, to Bound) iter.Seq2[interface{}, interface{}]
+	tr := newNatiral()
+	for _, i := range Ranges[2] {
+		tr.Ins(i, i)
+	}
+	for _, rg := range []struct {
+		from, to    Bound
+		first, last int
+	}{
+		{bound.Unbounded, bound.Unbounded, keyMin, keyMax},
+		{bound.Inclusive(10), bound.Unbounded, 10, keyMax},
+		{bound.Unbounded, bound.Inclusive(20), keyMin, 20},
+		{bound.Exclusive(10), bound.Exclusive(20), 11, 19},
+		{bound.Inclusive(0), bound.Inclusive(0), 0, 0},
+	} {
+		var i = rg.first
+		for k, v := range tr.Range(rg.from, rg.to) {
+			if k != i || v != i {
+				t.Fatal("wrong element", k, v, i, rg)
+			}
+			i++
+		}
+		if i != rg.last+1 {
+			t.Error("wrong end", i, rg)
+		}
+	}
+}
@@ -25,6 +25,13 @@
 
 package rb
 
+import (
+	"fmt"
+
+	"github.com/logrusorgru/gods/bound"
+	"github.com/logrusorgru/gods/printer"
+)
+
 type color bool
 
 const (
@@ -38,9 +45,14 @@ type EqualFunc func(a, b interface{}) bool
 
 type ZeroFunc func(a interface{}) bool
 
+// Bound of a range of the AscendRange or the DescendRange, use
+// bound.Inclusive, bound.Exclusive or bound.Unbounded.
+type Bound = bound.Bound
+
 type node struct {
 	d, l, r *node
 	c       color
+	s       int // size of the subtree, if the Tree is counted
 	k       interface{}
 	v       interface{}
 }
@@ -142,6 +154,51 @@ func (n *node) successor() (r *node) {
 	return
 }
 
+// next node in ascending order: min of the right subtree
+// or the first dad the n is at left of
+func (n *node) next() *node {
+	if n.r != nil {
+		for n = n.r; n.l != nil; n = n.l {
+		}
+		return n
+	}
+	for ; n.d != nil; n = n.d {
+		if n.d.l == n {
+			return n.d
+		}
+	}
+	return nil
+}
+
+// prev node in ascending order: max of the left subtree
+// or the first dad the n is at right of
+func (n *node) prev() *node {
+	if n.l != nil {
+		for n = n.l; n.r != nil; n = n.r {
+		}
+		return n
+	}
+	for ; n.d != nil; n = n.d {
+		if n.d.r == n {
+			return n.d
+		}
+	}
+	return nil
+}
+
+// size of subtree of the n
+func (n *node) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.s
+}
+
+// recount size of subtree of the n by its children
+func (n *node) recount() {
+	n.s = n.l.count() + n.r.count() + 1
+}
+
 func (n *node) replaceChild(old, new *node) {
 	if n.l == old {
 		n.l = new
@@ -166,7 +223,8 @@ type Tree struct {
 	equal EqualFunc
 	zero  ZeroFunc
 
-	size int
+	size    int
+	counted bool // nodes keep sizes of their subtrees
 }
 
 func New(less LessFunc, equal EqualFunc, zero ZeroFunc) (t *Tree) {
@@ -177,6 +235,15 @@ func New(less LessFunc, equal EqualFunc, zero ZeroFunc) (t *Tree) {
 	return
 }
 
+// NewCounted creates Tree that keeps size of subtree in every node.
+// It's a bit slower and bigger, but the Rank, the Select and the
+// CountRange of such Tree take O(log n) time.
+func NewCounted(less LessFunc, equal EqualFunc, zero ZeroFunc) (t *Tree) {
+	t = New(less, equal, zero)
+	t.counted = true
+	return
+}
+
 // findInsertNode finds node to insert to
 func (t *Tree) findInsertNode(d *node, k interface{}) *node {
 	for p, less := d, t.less; p != nil; { // p - place
@@ -232,6 +299,10 @@ func (t *Tree) rightRotate(n *node) {
 	}
 	n.d = pivot
 	pivot.r = n
+	if t.counted {
+		n.recount()
+		pivot.recount()
+	}
 }
 
 func (t *Tree) leftRotate(n *node) {
@@ -254,6 +325,10 @@ func (t *Tree) leftRotate(n *node) {
 	}
 	n.d = pivot
 	pivot.l = n
+	if t.counted {
+		n.recount()
+		pivot.recount()
+	}
 }
 
 func (t *Tree) insertLeftLeftBalancing(g, d *node) {
@@ -315,6 +390,7 @@ func (t *Tree) insertBalancing(d, n *node) {
 // to the d
 func (t *Tree) insertNode(d, n *node) {
 	t.size++
+	t.count(n, 1)
 	if d == nil {
 		t.r = n     // first element of the tree
 		n.c = black // root must be black
@@ -332,6 +408,17 @@ func (t *Tree) insertNode(d, n *node) {
 	t.insertBalancing(d, n)
 }
 
+// add given delta to sizes of subtrees of the n
+// and its ancestors, if the Tree is counted
+func (t *Tree) count(n *node, delta int) {
+	if !t.counted {
+		return
+	}
+	for ; n != nil; n = n.d {
+		n.s += delta
+	}
+}
+
 // Ins is insert or overwrite, returning
 //
 //     1. previous value, false
@@ -476,16 +563,19 @@ func (t *Tree) delBalancing(v *node) {
 				}
 			}
 			v.d.replaceChild(v, nil)
+			t.count(v.d, -1)
 			return
 		}
 		if v.l == nil || v.r == nil {
 			if t.isRoot(v) {
 				v.copy(u)
 				v.l, v.r = nil, nil
+				t.count(v, -1)
 				return
 			}
 			v.d.replaceChild(v, u)
 			u.d = v.d
+			t.count(u.d, -1)
 			if u.isBlack() && v.isBlack() {
 				t.fixDoubleBlack(u)
 				return
@@ -554,6 +644,114 @@ func (t *Tree) Max() (k, v interface{}, ok bool) {
 	return
 }
 
+// Floor returns the greatest key less than or equal to given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the last element of equal keys.
+func (t *Tree) Floor(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.upperNode(bound.Inclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Ceiling returns the least key greater than or equal to given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the first element of equal keys.
+func (t *Tree) Ceiling(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.lowerNode(bound.Inclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Lower returns the greatest key less than given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the last element of equal keys.
+func (t *Tree) Lower(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.upperNode(bound.Exclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Higher returns the least key greater than given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the first element of equal keys.
+func (t *Tree) Higher(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.lowerNode(bound.Exclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// below is number of elements below a range of the lower bound
+func (t *Tree) below(lo Bound) (c int) {
+	for n, less := t.r, t.less; n != nil; {
+		if lo.Below(less, n.k) {
+			c, n = c+n.l.count()+1, n.r
+		} else {
+			n = n.l
+		}
+	}
+	return
+}
+
+// above is number of elements above a range of the upper bound
+func (t *Tree) above(hi Bound) (c int) {
+	for n, less := t.r, t.less; n != nil; {
+		if hi.Above(less, n.k) {
+			c, n = c+n.r.count()+1, n.l
+		} else {
+			n = n.r
+		}
+	}
+	return
+}
+
+func (t *Tree) mustBeCounted(method string) {
+	if !t.counted {
+		panic("rb: " + method + " of Tree created without NewCounted")
+	}
+}
+
+// Rank returns number of elements less than given key, that is
+// index of the first element with the key, if the Tree contains
+// it. It panics if the Tree is not created by the NewCounted.
+func (t *Tree) Rank(k interface{}) int {
+	t.mustBeCounted("Rank")
+	return t.below(bound.Inclusive(k))
+}
+
+// Select returns key and value of the i-th element of the Tree
+// in ascending order starting from zero, or (nil, nil, false) if
+// the i is out of the Tree. It panics if the Tree is not created
+// by the NewCounted.
+func (t *Tree) Select(i int) (k, v interface{}, ok bool) {
+	t.mustBeCounted("Select")
+	for n := t.r; n != nil; {
+		switch l := n.l.count(); {
+		case i < l:
+			n = n.l
+		case i > l:
+			i, n = i-l-1, n.r
+		default:
+			return n.k, n.v, true
+		}
+	}
+	return
+}
+
+// CountRange returns number of elements in range from the lower
+// bound lo to the upper bound hi. It panics if the Tree is not
+// created by the NewCounted.
+func (t *Tree) CountRange(lo, hi Bound) (c int) {
+	t.mustBeCounted("CountRange")
+	if c = t.size - t.below(lo) - t.above(hi); c < 0 {
+		c = 0 // empty range
+	}
+	return
+}
+
 func (t *Tree) Size() int {
 	return t.size
 }
@@ -586,287 +784,211 @@ func (t *Tree) Walk(walkFunc WalkFunc) {
 	walk(t.r, walkFunc) // recursive
 }
 
-// [from, +inf)
-func (t *Tree) ascendFrom(from interface{}, ascendFunc WalkFunc) {
-	var n *node
-	if _, n = t.findNode(from); n == nil {
-		if n = t.minNode(); n != nil && t.less(n.k, from) {
-			return
-		}
-	}
-	for n != nil {
+// (-inf, +inf)
+func (t *Tree) ascend(ascendFunc WalkFunc) {
+	for n := t.minNode(); n != nil; {
 		if !ascendFunc(n.k, n.v) {
 			return
 		}
-		if n.r != nil {
-			n = n.r
-			for n.l != nil {
-				n = n.l
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.l == n {
-				n = n.d
-				break
-			}
-		}
+		n = n.next()
 	}
 }
 
-// (-inf, to]
-func (t *Tree) ascendTo(to interface{}, ascendFunc WalkFunc) {
-	var (
-		n    = t.minNode()
-		less = t.less
-	)
-	for n != nil {
-		if less(to, n.k) {
-			return // that's all
-		}
-		if !ascendFunc(n.k, n.v) {
-			return
-		}
-		if n.r != nil {
+// the first node of a range of the lower bound
+func (t *Tree) lowerNode(lo Bound) (s *node) {
+	for n, less := t.r, t.less; n != nil; {
+		if lo.Below(less, n.k) {
 			n = n.r
-			for n.l != nil {
-				n = n.l
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.l == n {
-				n = n.d
-				break
-			}
+		} else {
+			s, n = n, n.l
 		}
 	}
+	return
 }
 
-// [from, to]
-func (t *Tree) ascendFromTo(from, to interface{}, ascendFunc WalkFunc) {
-	var (
-		n    *node
-		less = t.less
-	)
-	if _, n = t.findNode(from); n == nil {
-		if n = t.minNode(); n != nil && t.less(n.k, from) {
-			return
-		}
-	}
-	for n != nil {
-		if less(to, n.k) {
-			return // that's all
-		}
-		if !ascendFunc(n.k, n.v) {
-			return
-		}
-		if n.r != nil {
-			n = n.r
-			for n.l != nil {
-				n = n.l
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.l == n {
-				n = n.d
-				break
-			}
-		}
+// the zero key is unbounded, other keys are inclusive
+func (t *Tree) zeroBound(k interface{}) Bound {
+	if t.zero(k) {
+		return bound.Unbounded
 	}
+	return bound.Inclusive(k)
 }
 
-// (-inf, +inf)
-func (t *Tree) ascend(ascendFunc WalkFunc) {
-	for n := t.minNode(); n != nil; {
+// AscendRange iterates elements of the Tree in ascending order
+// from the lower bound from to the upper bound to. Unlike the
+// Ascend, a bound can be any key, including zero one.
+func (t *Tree) AscendRange(from, to Bound, ascendFunc WalkFunc) {
+	var less = t.less
+	for n := t.lowerNode(from); n != nil; n = n.next() {
+		if to.Above(less, n.k) {
+			return // that's all
+		}
 		if !ascendFunc(n.k, n.v) {
 			return
 		}
-		if n.r != nil {
-			n = n.r
-			for n.l != nil {
-				n = n.l
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.l == n {
-				n = n.d
-				break
-			}
-		}
 	}
 }
 
 // Ascend iterates elements of the tree ascending order. The ZeroFunc
-// used to determine ascending range.
+// used to determine ascending range: a zero from or to is unbounded,
+// other keys are inclusive bounds. See also the AscendRange.
 func (t *Tree) Ascend(from, to interface{}, ascendFunc WalkFunc) {
-	// zero function
-	switch zero := t.zero; {
-	case zero(from): // (-inf, to] or (-inf, +inf)
-		if zero(to) {
-			t.ascend(ascendFunc) // (-inf, +inf)
-		} else {
-			t.ascendTo(to, ascendFunc) // (-inf, to]
-		}
-	case zero(to): // [from, +inf)
-		t.ascendFrom(from, ascendFunc)
-	default: // [from, to]
-		t.ascendFromTo(from, to, ascendFunc)
-	}
+	t.AscendRange(t.zeroBound(from), t.zeroBound(to), ascendFunc)
 }
 
-// [from, -inf) (reversed)
-func (t *Tree) descendFrom(from interface{}, descendFunc WalkFunc) {
-	var n *node
-	if _, n = t.findNode(from); n == nil {
-		if n = t.maxNode(); n != nil && t.less(from, n.k) {
-			return
-		}
-	}
-	for n != nil {
+// (-inf, +inf) (reversed)
+func (t *Tree) descend(descendFunc WalkFunc) {
+	for n := t.maxNode(); n != nil; {
 		if !descendFunc(n.k, n.v) {
 			return
 		}
-		if n.l != nil {
+		n = n.prev()
+	}
+}
+
+// the last node of a range of the upper bound
+func (t *Tree) upperNode(hi Bound) (s *node) {
+	for n, less := t.r, t.less; n != nil; {
+		if hi.Above(less, n.k) {
 			n = n.l
-			for n.r != nil {
-				n = n.r
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.r == n {
-				n = n.d
-				break
-			}
+		} else {
+			s, n = n, n.r
 		}
 	}
+	return
 }
 
-// (+inf, to] (reversed)
-func (t *Tree) descendTo(to interface{}, descendFunc WalkFunc) {
-	var (
-		n    = t.maxNode()
-		less = t.less
-	)
-	for n != nil {
-		if less(n.k, to) {
+// DescendRange iterates elements of the Tree in descending order
+// from the upper bound from to the lower bound to. Unlike the
+// Descend, a bound can be any key, including zero one.
+func (t *Tree) DescendRange(from, to Bound, descendFunc WalkFunc) {
+	var less = t.less
+	for n := t.upperNode(from); n != nil; n = n.prev() {
+		if to.Below(less, n.k) {
 			return // that's all
 		}
 		if !descendFunc(n.k, n.v) {
 			return
 		}
-		if n.l != nil {
-			n = n.l
-			for n.r != nil {
-				n = n.r
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.r == n {
-				n = n.d
-				break
-			}
-		}
 	}
 }
 
-// [from, to] (reversed)
-func (t *Tree) descendFromTo(from, to interface{}, descendFunc WalkFunc) {
-	var (
-		n    *node
-		less = t.less
-	)
-	if _, n = t.findNode(from); n == nil {
-		if n = t.maxNode(); n != nil && t.less(from, n.k) {
-			return
-		}
+// Descend iterates elements of the tree descending order. The
+// ZeroFunc used to determine descending range like the Ascend
+// does. See also the DescendRange.
+func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
+	t.DescendRange(t.zeroBound(from), t.zeroBound(to), descendFunc)
+}
+
+// A Cursor is position in the Tree. Unlike the Ascend and
+// the Descend, a Cursor can pause, step back and forth, and
+// iterate many trees at the same time. A change of the Tree
+// invalidates its cursors, except the InsEx that overwrites
+// value only. A new Cursor is not valid, use First, Last or
+// Seek to point it to an element.
+type Cursor struct {
+	t *Tree
+	n *node
+}
+
+// Cursor of the Tree.
+func (t *Tree) Cursor() *Cursor {
+	return &Cursor{t: t}
+}
+
+// First moves the Cursor to the min element. It returns
+// false if the Tree is empty.
+func (c *Cursor) First() bool {
+	c.n = c.t.minNode()
+	return c.n != nil
+}
+
+// Last moves the Cursor to the max element. It returns
+// false if the Tree is empty.
+func (c *Cursor) Last() bool {
+	c.n = c.t.maxNode()
+	return c.n != nil
+}
+
+// Seek moves the Cursor to the first element with key greater
+// than or equal to given. It returns false if there is no such
+// element.
+func (c *Cursor) Seek(k interface{}) bool {
+	c.n = c.t.lowerNode(bound.Inclusive(k))
+	return c.n != nil
+}
+
+// Next moves the Cursor to the next element in ascending order.
+// It returns false if the Cursor reaches end of the Tree or it's
+// not valid.
+func (c *Cursor) Next() bool {
+	if c.n != nil {
+		c.n = c.n.next()
 	}
-	for n != nil {
-		if less(n.k, to) {
-			return // that's all
-		}
-		if !descendFunc(n.k, n.v) {
-			return
-		}
-		if n.l != nil {
-			n = n.l
-			for n.r != nil {
-				n = n.r
-			}
-			continue
-		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.r == n {
-				n = n.d
-				break
-			}
-		}
+	return c.n != nil
+}
+
+// Prev moves the Cursor to the previous element in ascending
+// order. It returns false if the Cursor reaches beginning of the
+// Tree or it's not valid.
+func (c *Cursor) Prev() bool {
+	if c.n != nil {
+		c.n = c.n.prev()
 	}
+	return c.n != nil
 }
 
-// (-inf, +inf) (reversed)
-func (t *Tree) descend(descendFunc WalkFunc) {
-	for n := t.maxNode(); n != nil; {
-		if !descendFunc(n.k, n.v) {
-			return
-		}
-		if n.l != nil {
-			n = n.l
-			for n.r != nil {
-				n = n.r
-			}
+// Valid returns true if the Cursor points to an element.
+func (c *Cursor) Valid() bool {
+	return c.n != nil
+}
+
+// Key of the element or nil if the Cursor is not valid.
+func (c *Cursor) Key() interface{} {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.k
+}
+
+// Value of the element or nil if the Cursor is not valid.
+func (c *Cursor) Value() interface{} {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.v
+}
+
+// Printer prints a Tree, use printer.New
+// to print to string
+type Printer = printer.Printer
+
+func (c color) String() string {
+	if c == red {
+		return "R"
+	}
+	return "B"
+}
+
+// print subtree of the n
+func (n *node) print(p Printer) {
+	p = p.Add(fmt.Sprintf("[%s] %v", n.c, n.k))
+	if n.l == nil && n.r == nil {
+		return
+	}
+	for _, c := range []*node{n.l, n.r} {
+		if c == nil {
+			p.Add("nil") // keep left and right
 			continue
 		}
-		for ; ; n = n.d {
-			if n.d == nil {
-				return
-			}
-			if n.d.r == n {
-				n = n.d
-				break
-			}
-		}
+		c.print(p)
 	}
 }
 
-// Descend iterates elements of the tree descending order.
-func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
-	// zero function
-	switch zero := t.zero; {
-	case zero(from): // (-inf, to] or (-inf, +inf)
-		if zero(to) {
-			t.descend(descendFunc) // (-inf, +inf)
-		} else {
-			t.descendTo(to, descendFunc) // (-inf, to]
-		}
-	case zero(to): // [from, +inf)
-		t.descendFrom(from, descendFunc)
-	default: // [from, to]
-		t.descendFromTo(from, to, descendFunc)
+// Print the Tree to given Printer. Labels of nodes
+// are colours and keys, like "[R] 5".
+func (t *Tree) Print(p Printer) {
+	if t.r != nil {
+		t.r.print(p)
 	}
 }
@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+//go:build go1.23
+
+package srb
+
+import (
+	"iter"
+)
+
+// All returns iterator over elements of the Tree in ascending
+// order. The Tree must not be changed during the iteration.
+//
+//	for k, v := range tree.All() {
+//	    // use k and v
+//	}
+func (t *Tree) All() iter.Seq2[interface{}, interface{}] {
+	return func(yield func(k, v interface{}) bool) {
+		t.ascend(yield)
+	}
+}
+
+// Backward returns iterator over elements of the Tree in
+// descending order.
+func (t *Tree) Backward() iter.Seq2[interface{}, interface{}] {
+	return func(yield func(k, v interface{}) bool) {
+		t.descend(yield)
+	}
+}
+
+// Range returns iterator over elements from the lower bound
+// from to the upper bound to in ascending order.
+func (t *Tree) Range(from, to Bound) iter.Seq2[interface{}, interface{}] {
+	return func(yield func(k, v interface{}) bool) {
+		t.AscendRange(from, to, yield)
+	}
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+//go:build go1.23
+
+package srb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/logrusorgru/gods/bound"
+)
+
+// tree of the r keys without random half of them and the
+// keys left in ascending order, the deletions change depth
+// of branches the stack of an iteration keeps
+func newDeleted(r []int) (tr *Tree, keys []int) {
+	tr = newNatiral()
+	for _, i := range r {
+		tr.Ins(i, i)
+	}
+	var del = make(map[int]bool)
+	for _, i := range rand.Perm(keyMax + 1)[:keyMax/2] {
+		tr.Del(i)
+		del[i] = true
+	}
+	for i := keyMin; i <= keyMax; i++ {
+		if !del[i] {
+			keys = append(keys, i)
+		}
+	}
+	return
+}
+
+func TestTree_All(t *testing.T) {
+	// All() iter.Seq2[interface{}, interface{}]
+	for _, r := range Ranges {
+		var tr, keys = newDeleted(r)
+		var i int
+		for k, v := range tr.All() {
+			if i == len(keys) || k != keys[i] || v != keys[i] {
+				t.Fatal("wrong element", k, v, i, rs(r))
+			}
+			i++
+		}
+		if i != len(keys) {
+			t.Fatal("wrong end", i, len(keys), rs(r))
+		}
+	}
+}
+
+func TestTree_Backward(t *testing.T) {
+	// Backward() iter.Seq2[interface{}, interface{}]
+	for _, r := range Ranges {
+		var tr, keys = newDeleted(r)
+		var i = len(keys)
+		for k, v := range tr.Backward() {
+			if i--; i < 0 || k != keys[i] || v != keys[i] {
+				t.Fatal("wrong element", k, v, i, rs(r))
+			}
+		}
+		if i != 0 {
+			t.Fatal("wrong end", i, rs(r))
+		}
+	}
+}
+
+func TestTree_Backward_stop(t *testing.T) {
+	// the yield is not called after it returns false
+	var tr, keys = newDeleted(Ranges[2])
+	for n := 1; n <= len(keys); n++ {
+		var calls int
+		tr.Backward()(func(k, v interface{}) bool {
+			calls++
+			if calls > n {
+				t.Fatal("yield after stop", n, calls, k)
+			}
+			if k != keys[len(keys)-calls] {
+				t.Fatal("wrong element", n, calls, k)
+			}
+			return calls < n
+		})
+		if calls != n {
+			t.Fatal("wrong stop", n, calls)
+		}
+	}
+}
+
+func TestTree_Range(t *testing.T) {
+	// Range(from, to Bound) iter.Seq2[interface{}, interface{}]
+	var tr, keys = newDeleted(Ranges[2])
+	var bs = []Bound{bound.Unbounded}
+	for i := keyMin - 1; i <= keyMax+1; i++ {
+		bs = append(bs, bound.Inclusive(i), bound.Exclusive(i))
+	}
+	var in = func(b Bound, k int, lower bool) bool {
+		if b.IsUnbounded() {
+			return true
+		}
+		var bk = b.Key().(int)
+		if b.IsExclusive() && k == bk {
+			return false
+		}
+		return lower && k >= bk || !lower && k <= bk
+	}
+	for _, from := range bs {
+		for _, to := range bs {
+			var want []int
+			for _, k := range keys {
+				if in(from, k, true) && in(to, k, false) {
+					want = append(want, k)
+				}
+			}
+			var i int
+			for k, v := range tr.Range(from, to) {
+				if i == len(want) || k != want[i] || v != want[i] {
+					t.Fatal("wrong element", k, v, i, from, to)
+				}
+				i++
+			}
+			if i != len(want) {
+				t.Fatal("wrong end", i, len(want), from, to)
+			}
+		}
+	}
+}
@@ -25,6 +25,13 @@
 
 package srb
 
+import (
+	"fmt"
+
+	"github.com/logrusorgru/gods/bound"
+	"github.com/logrusorgru/gods/printer"
+)
+
 type color bool
 
 const (
@@ -38,6 +45,10 @@ type EqualFunc func(a, b interface{}) bool
 
 type ZeroFunc func(a interface{}) bool
 
+// Bound of a range of the AscendRange or the DescendRange, use
+// bound.Inclusive, bound.Exclusive or bound.Unbounded.
+type Bound = bound.Bound
+
 type node struct {
 	l, r *node
 	c    color
@@ -545,6 +556,72 @@ func (t *Tree) Max() (k, v interface{}, ok bool) {
 	return
 }
 
+// the first node of a range of the lower bound, unlike
+// the lowerNode it doesn't track ancestors of the node
+func (t *Tree) firstNode(lo Bound) (s *node) {
+	for n, less := t.r, t.less; n != nil; {
+		if lo.Below(less, n.k) {
+			n = n.r
+		} else {
+			s, n = n, n.l
+		}
+	}
+	return
+}
+
+// the last node of a range of the upper bound, unlike
+// the upperNode it doesn't track ancestors of the node
+func (t *Tree) lastNode(hi Bound) (s *node) {
+	for n, less := t.r, t.less; n != nil; {
+		if hi.Above(less, n.k) {
+			n = n.l
+		} else {
+			s, n = n, n.r
+		}
+	}
+	return
+}
+
+// Floor returns the greatest key less than or equal to given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the last element of equal keys.
+func (t *Tree) Floor(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.lastNode(bound.Inclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Ceiling returns the least key greater than or equal to given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the first element of equal keys.
+func (t *Tree) Ceiling(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.firstNode(bound.Inclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Lower returns the greatest key less than given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the last element of equal keys.
+func (t *Tree) Lower(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.lastNode(bound.Exclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
+// Higher returns the least key greater than given one and its
+// value, or (nil, nil, false) if there is no such key. If the Tree
+// is not unique, it returns the first element of equal keys.
+func (t *Tree) Higher(key interface{}) (k, v interface{}, ok bool) {
+	if n := t.firstNode(bound.Exclusive(key)); n != nil {
+		k, v, ok = n.k, n.v, true
+	}
+	return
+}
+
 func (t *Tree) Size() int {
 	return t.size
 }
@@ -577,35 +654,9 @@ func (t *Tree) Walk(walkFunc WalkFunc) {
 	walk(t.r, walkFunc) // recursive
 }
 
-func (t *Tree) findAscendNode(k interface{}) (st []*node, n *node) {
-	var (
-		less  = t.less
-		equal = t.equal
-	)
-	for n = t.r; n != nil; {
-		switch {
-		case equal(k, n.k):
-			return
-		case less(k, n.k):
-			st = append(st, n)
-			n = n.l
-		default:
-			n = n.r
-		}
-	}
-	return
-}
-
-// [from, +inf)
-func (t *Tree) ascendFrom(from interface{}, ascendFunc WalkFunc) {
-	var st, n = t.findAscendNode(from)
-	if n == nil {
-		if st, n = t.minNode(); n != nil && t.less(n.k, from) {
-			return
-		}
-	}
-	var d *node
-	for n != nil {
+// (-inf, +inf)
+func (t *Tree) ascend(ascendFunc WalkFunc) {
+	for st, n := t.minNode(); n != nil; {
 		if !ascendFunc(n.k, n.v) {
 			return
 		}
@@ -616,55 +667,43 @@ func (t *Tree) ascendFrom(from interface{}, ascendFunc WalkFunc) {
 				n = n.l
 			}
 		} else {
-			if st, d = pop(st); d == nil {
-				return
-			} else if d.r == n {
-				st, n = pop(st)
-			} else {
-				n = d
-			}
+			st, n = pop(st)
 		}
 	}
 }
 
-// (-inf, to]
-func (t *Tree) ascendTo(to interface{}, ascendFunc WalkFunc) {
-	var (
-		st, n = t.minNode()
-		less  = t.less
-	)
-	for n != nil {
-		if less(to, n.k) {
-			return // that's all
-		}
-		if !ascendFunc(n.k, n.v) {
-			return
-		}
-		if n.r != nil {
-			n = n.r
-			for n.l != nil {
-				st = append(st, n)
-				n = n.l
-			}
+// the first node of a range of the lower bound and
+// its ancestors greater than the node
+func (t *Tree) lowerNode(lo Bound) (st []*node, n *node) {
+	for x, less := t.r, t.less; x != nil; {
+		if lo.Below(less, x.k) {
+			x = x.r
 		} else {
-			st, n = pop(st)
+			st, x = append(st, x), x.l
 		}
 	}
+	st, n = pop(st)
+	return
 }
 
-// [from, to]
-func (t *Tree) ascendFromTo(from, to interface{}, ascendFunc WalkFunc) {
+// the zero key is unbounded, other keys are inclusive
+func (t *Tree) zeroBound(k interface{}) Bound {
+	if t.zero(k) {
+		return bound.Unbounded
+	}
+	return bound.Inclusive(k)
+}
+
+// AscendRange iterates elements of the Tree in ascending order
+// from the lower bound from to the upper bound to. Unlike the
+// Ascend, a bound can be any key, including zero one.
+func (t *Tree) AscendRange(from, to Bound, ascendFunc WalkFunc) {
 	var (
-		st, n = t.findAscendNode(from)
+		st, n = t.lowerNode(from)
 		less  = t.less
 	)
-	if n == nil {
-		if st, n = t.minNode(); n != nil && t.less(n.k, from) {
-			return
-		}
-	}
 	for n != nil {
-		if less(to, n.k) {
+		if to.Above(less, n.k) {
 			return // that's all
 		}
 		if !ascendFunc(n.k, n.v) {
@@ -682,70 +721,16 @@ func (t *Tree) ascendFromTo(from, to interface{}, ascendFunc WalkFunc) {
 	}
 }
 
-// (-inf, +inf)
-func (t *Tree) ascend(ascendFunc WalkFunc) {
-	for st, n := t.minNode(); n != nil; {
-		if !ascendFunc(n.k, n.v) {
-			return
-		}
-		if n.r != nil {
-			n = n.r
-			for n.l != nil {
-				st = append(st, n)
-				n = n.l
-			}
-		} else {
-			st, n = pop(st)
-		}
-	}
-}
-
 // Ascend iterates elements of the tree ascending order. The ZeroFunc
-// used to determine ascending range.
+// used to determine ascending range: a zero from or to is unbounded,
+// other keys are inclusive bounds. See also the AscendRange.
 func (t *Tree) Ascend(from, to interface{}, ascendFunc WalkFunc) {
-	// zero function
-	switch zero := t.zero; {
-	case zero(from): // (-inf, to] or (-inf, +inf)
-		if zero(to) {
-			t.ascend(ascendFunc) // (-inf, +inf)
-		} else {
-			t.ascendTo(to, ascendFunc) // (-inf, to]
-		}
-	case zero(to): // [from, +inf)
-		t.ascendFrom(from, ascendFunc)
-	default: // [from, to]
-		t.ascendFromTo(from, to, ascendFunc)
-	}
-}
-
-func (t *Tree) findDescendNode(k interface{}) (st []*node, n *node) {
-	var (
-		less  = t.less
-		equal = t.equal
-	)
-	for n = t.r; n != nil; {
-		switch {
-		case equal(k, n.k):
-			return
-		case less(k, n.k):
-			n = n.l
-		default:
-			st = append(st, n)
-			n = n.r
-		}
-	}
-	return
+	t.AscendRange(t.zeroBound(from), t.zeroBound(to), ascendFunc)
 }
 
-// [from, -inf) (reversed)
-func (t *Tree) descendFrom(from interface{}, descendFunc WalkFunc) {
-	var st, n = t.findDescendNode(from)
-	if n == nil {
-		if st, n = t.maxNode(); n != nil && t.less(from, n.k) {
-			return
-		}
-	}
-	for n != nil {
+// (-inf, +inf) (reversed)
+func (t *Tree) descend(descendFunc WalkFunc) {
+	for st, n := t.maxNode(); n != nil; {
 		if !descendFunc(n.k, n.v) {
 			return
 		}
@@ -761,14 +746,30 @@ func (t *Tree) descendFrom(from interface{}, descendFunc WalkFunc) {
 	}
 }
 
-// (+inf, to] (reversed)
-func (t *Tree) descendTo(to interface{}, descendFunc WalkFunc) {
+// the last node of a range of the upper bound and
+// its ancestors less than the node
+func (t *Tree) upperNode(hi Bound) (st []*node, n *node) {
+	for x, less := t.r, t.less; x != nil; {
+		if hi.Above(less, x.k) {
+			x = x.l
+		} else {
+			st, x = append(st, x), x.r
+		}
+	}
+	st, n = pop(st)
+	return
+}
+
+// DescendRange iterates elements of the Tree in descending order
+// from the upper bound from to the lower bound to. Unlike the
+// Descend, a bound can be any key, including zero one.
+func (t *Tree) DescendRange(from, to Bound, descendFunc WalkFunc) {
 	var (
-		st, n = t.maxNode()
+		st, n = t.upperNode(from)
 		less  = t.less
 	)
 	for n != nil {
-		if less(n.k, to) {
+		if to.Below(less, n.k) {
 			return // that's all
 		}
 		if !descendFunc(n.k, n.v) {
@@ -786,67 +787,177 @@ func (t *Tree) descendTo(to interface{}, descendFunc WalkFunc) {
 	}
 }
 
-// [from, to] (reversed)
-func (t *Tree) descendFromTo(from, to interface{}, descendFunc WalkFunc) {
-	var (
-		st, n = t.findDescendNode(from)
-		less  = t.less
-	)
-	if n == nil {
-		if st, n = t.maxNode(); n != nil && t.less(from, n.k) {
-			return
+// Descend iterates elements of the tree descending order. The
+// ZeroFunc used to determine descending range like the Ascend
+// does. See also the DescendRange.
+func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
+	t.DescendRange(t.zeroBound(from), t.zeroBound(to), descendFunc)
+}
+
+// next node in ascending order and path to it, the st is
+// path from the root to the n (the n excluded)
+func next(st []*node, n *node) ([]*node, *node) {
+	if n.r != nil {
+		for st, n = append(st, n), n.r; n.l != nil; n = n.l {
+			st = append(st, n)
 		}
+		return st, n
 	}
-	for n != nil {
-		if less(n.k, to) {
-			return // that's all
+	var d *node
+	for {
+		if st, d = pop(st); d == nil || d.l == n {
+			return st, d
 		}
-		if !descendFunc(n.k, n.v) {
-			return
+		n = d
+	}
+}
+
+// prev node in ascending order and path to it, the st is
+// path from the root to the n (the n excluded)
+func prev(st []*node, n *node) ([]*node, *node) {
+	if n.l != nil {
+		for st, n = append(st, n), n.l; n.r != nil; n = n.r {
+			st = append(st, n)
 		}
-		if n.l != nil {
-			n = n.l
-			for n.r != nil {
-				st = append(st, n)
-				n = n.r
-			}
-		} else {
-			st, n = pop(st)
+		return st, n
+	}
+	var d *node
+	for {
+		if st, d = pop(st); d == nil || d.r == n {
+			return st, d
 		}
+		n = d
 	}
 }
 
-// (-inf, +inf) (reversed)
-func (t *Tree) descend(descendFunc WalkFunc) {
-	for st, n := t.maxNode(); n != nil; {
-		if !descendFunc(n.k, n.v) {
-			return
-		}
-		if n.l != nil {
-			n = n.l
-			for n.r != nil {
-				st = append(st, n)
-				n = n.r
-			}
+// the first node with key greater than or equal to the k
+// and path to it
+func (t *Tree) seekNode(k interface{}) (st []*node, s *node) {
+	var depth int
+	for n, less := t.r, t.less; n != nil; {
+		if less(n.k, k) {
+			st = append(st, n)
+			n = n.r
 		} else {
-			st, n = pop(st)
+			s, depth = n, len(st)
+			st = append(st, n)
+			n = n.l
 		}
 	}
+	return st[:depth], s
 }
 
-// Descend iterates elements of the tree descending order.
-func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
-	// zero function
-	switch zero := t.zero; {
-	case zero(from): // (-inf, to] or (-inf, +inf)
-		if zero(to) {
-			t.descend(descendFunc) // (-inf, +inf)
-		} else {
-			t.descendTo(to, descendFunc) // (-inf, to]
+// A Cursor is position in the Tree. Unlike the Ascend and
+// the Descend, a Cursor can pause, step back and forth, and
+// iterate many trees at the same time. A Cursor keeps path
+// from the root to its element. A change of the Tree
+// invalidates its cursors, except the InsEx that overwrites
+// value only. A new Cursor is not valid, use First, Last or
+// Seek to point it to an element.
+type Cursor struct {
+	t  *Tree
+	st []*node // path to the n
+	n  *node
+}
+
+// Cursor of the Tree.
+func (t *Tree) Cursor() *Cursor {
+	return &Cursor{t: t}
+}
+
+// First moves the Cursor to the min element. It returns
+// false if the Tree is empty.
+func (c *Cursor) First() bool {
+	c.st, c.n = c.t.minNode()
+	return c.n != nil
+}
+
+// Last moves the Cursor to the max element. It returns
+// false if the Tree is empty.
+func (c *Cursor) Last() bool {
+	c.st, c.n = c.t.maxNode()
+	return c.n != nil
+}
+
+// Seek moves the Cursor to the first element with key greater
+// than or equal to given. It returns false if there is no such
+// element.
+func (c *Cursor) Seek(k interface{}) bool {
+	c.st, c.n = c.t.seekNode(k)
+	return c.n != nil
+}
+
+// Next moves the Cursor to the next element in ascending order.
+// It returns false if the Cursor reaches end of the Tree or it's
+// not valid.
+func (c *Cursor) Next() bool {
+	if c.n != nil {
+		c.st, c.n = next(c.st, c.n)
+	}
+	return c.n != nil
+}
+
+// Prev moves the Cursor to the previous element in ascending
+// order. It returns false if the Cursor reaches beginning of the
+// Tree or it's not valid.
+func (c *Cursor) Prev() bool {
+	if c.n != nil {
+		c.st, c.n = prev(c.st, c.n)
+	}
+	return c.n != nil
+}
+
+// Valid returns true if the Cursor points to an element.
+func (c *Cursor) Valid() bool {
+	return c.n != nil
+}
+
+// Key of the element or nil if the Cursor is not valid.
+func (c *Cursor) Key() interface{} {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.k
+}
+
+// Value of the element or nil if the Cursor is not valid.
+func (c *Cursor) Value() interface{} {
+	if c.n == nil {
+		return nil
+	}
+	return c.n.v
+}
+
+// Printer prints a Tree, use printer.New
+// to print to string
+type Printer = printer.Printer
+
+func (c color) String() string {
+	if c == red {
+		return "R"
+	}
+	return "B"
+}
+
+// print subtree of the n
+func (n *node) print(p Printer) {
+	p = p.Add(fmt.Sprintf("[%s] %v", n.c, n.k))
+	if n.l == nil && n.r == nil {
+		return
+	}
+	for _, c := range []*node{n.l, n.r} {
+		if c == nil {
+			p.Add("nil") // keep left and right
+			continue
 		}
-	case zero(to): // [from, +inf)
-		t.descendFrom(from, descendFunc)
-	default: // [from, to]
-		t.descendFromTo(from, to, descendFunc)
+		c.print(p)
+	}
+}
+
+// Print the Tree to given Printer. Labels of nodes
+// are colours and keys, like "[R] 5".
+func (t *Tree) Print(p Printer) {
+	if t.r != nil {
+		t.r.print(p)
 	}
 }
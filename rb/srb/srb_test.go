@@ -29,6 +29,9 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+
+	"github.com/logrusorgru/gods/bound"
+	"github.com/logrusorgru/gods/printer"
 )
 
 const (
@@ -1085,3 +1088,304 @@ func TestTree_Descend(t *testing.T) {
 	})
 
 }
+
+func TestTree_Print(t *testing.T) {
+	// Print(p Printer)
+
+	var tr = newNatiral()
+	var printed = func() string {
+		var p = printer.New("tree")
+		tr.Print(p)
+		return p.String()
+	}
+	if got := printed(); got != "tree\n" {
+		t.Errorf("empty tree printed as %q", got)
+	}
+	tr.Ins(1, 1)
+	tr.Ins(2, 2)
+	var want = "tree\n" +
+		"`-- [B] 1\n" +
+		"    |-- nil\n" +
+		"    `-- [R] 2\n"
+	if got := printed(); got != want {
+		t.Errorf("wrong tree\n%s\nwant\n%s", got, want)
+	}
+	tr.Ins(3, 3)
+	want = "tree\n" +
+		"`-- [B] 2\n" +
+		"    |-- [R] 1\n" +
+		"    `-- [R] 3\n"
+	if got := printed(); got != want {
+		t.Errorf("wrong tree\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTree_Cursor(t *testing.T) {
+	// Cursor() *Cursor
+
+	t.Run("empty", func(t *testing.T) {
+		var c = newNatiral().Cursor()
+		if c.Valid() || c.Key() != nil || c.Value() != nil {
+			t.Error("new cursor is valid")
+		}
+		if c.First() || c.Last() || c.Seek(1) || c.Next() || c.Prev() {
+			t.Error("cursor of empty tree is valid")
+		}
+	})
+
+	t.Run("next", func(t *testing.T) {
+		for _, r := range Ranges {
+			tr := newNatiral()
+			for _, i := range r {
+				tr.Ins(i, i)
+			}
+			var c, i = tr.Cursor(), keyMin
+			for ok := c.First(); ok; ok = c.Next() {
+				if c.Key() != i || c.Value() != i {
+					t.Fatal("wrong element", c.Key(), c.Value(), i, rs(r))
+				}
+				i++
+			}
+			if i != keyMax+1 || c.Valid() {
+				t.Fatal("wrong end", i, rs(r))
+			}
+		}
+	})
+
+	t.Run("prev", func(t *testing.T) {
+		for _, r := range Ranges {
+			tr := newNatiral()
+			for _, i := range r {
+				tr.Ins(i, i)
+			}
+			var c, i = tr.Cursor(), keyMax
+			for ok := c.Last(); ok; ok = c.Prev() {
+				if c.Key() != i || c.Value() != i {
+					t.Fatal("wrong element", c.Key(), c.Value(), i, rs(r))
+				}
+				i--
+			}
+			if i != keyMin-1 || c.Valid() {
+				t.Fatal("wrong end", i, rs(r))
+			}
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		for _, r := range Ranges {
+			tr := newNatiral()
+			for _, i := range r {
+				tr.Ins(2*i, i) // even keys
+			}
+			var c = tr.Cursor()
+			for k := keyMin - 1; k <= 2*keyMax; k++ {
+				var want = k + k&1 // next even
+				if want < keyMin {
+					want = keyMin
+				}
+				if !c.Seek(k) || c.Key() != want {
+					t.Fatal("wrong seek", k, c.Key(), rs(r))
+				}
+				if want > keyMin && (!c.Prev() || c.Key() != want-2 ||
+					!c.Next() || c.Key() != want) {
+					t.Fatal("can't step back", k, c.Key(), rs(r))
+				}
+			}
+			if c.Seek(2*keyMax+1) || c.Valid() {
+				t.Fatal("seek above max", c.Key())
+			}
+		}
+	})
+
+	t.Run("not unique", func(t *testing.T) {
+		tr := newNatiral()
+		for i := keyMin; i <= keyMax; i++ {
+			tr.Add(i, i)
+			tr.Add(i, -i)
+		}
+		var c = tr.Cursor()
+		for i := keyMin + 1; i <= keyMax; i++ {
+			if !c.Seek(i) || !c.Prev() || c.Key() != i-1 {
+				t.Fatal("seek is not the first of equal keys", i, c.Key())
+			}
+		}
+		var called int
+		for ok := c.First(); ok; ok = c.Next() {
+			called++
+		}
+		if called != tr.Size() {
+			t.Error("wrong called", called, tr.Size())
+		}
+	})
+
+	t.Run("interleave", func(t *testing.T) {
+		a, b := newNatiral(), newNatiral()
+		for i := keyMin; i <= keyMax; i++ {
+			if i%2 == 0 {
+				a.Ins(i, i)
+			} else {
+				b.Ins(i, i)
+			}
+		}
+		var (
+			ca, cb = a.Cursor(), b.Cursor()
+			okA    = ca.First()
+			okB    = cb.First()
+			i      = keyMin
+		)
+		for okA || okB {
+			if okA && (!okB || ca.Key().(int) < cb.Key().(int)) {
+				if ca.Key() != i {
+					t.Fatal("wrong key", ca.Key(), i)
+				}
+				okA = ca.Next()
+			} else {
+				if cb.Key() != i {
+					t.Fatal("wrong key", cb.Key(), i)
+				}
+				okB = cb.Next()
+			}
+			i++
+		}
+		if i != keyMax+1 {
+			t.Error("wrong end", i)
+		}
+	})
+}
+
+// bounds of the AscendRange and DescendRange tests
+func testBounds() (bs []Bound) {
+	bs = append(bs, bound.Unbounded)
+	for _, k := range []int{keyMin - 1, keyMin, 37, 50, keyMax, keyMax + 1} {
+		bs = append(bs, bound.Inclusive(k), bound.Exclusive(k))
+	}
+	return
+}
+
+// is the k in range of the lower bound lo and the upper bound hi
+func inBounds(lo, hi Bound, k int) bool {
+	switch {
+	case lo.IsInclusive() && k < lo.Key().(int),
+		lo.IsExclusive() && k <= lo.Key().(int),
+		hi.IsInclusive() && k > hi.Key().(int),
+		hi.IsExclusive() && k >= hi.Key().(int):
+		return false
+	}
+	return true
+}
+
+func TestTree_AscendRange(t *testing.T) {
+	// AscendRange(from, to Bound, ascendFunc WalkFunc)
+	for _, r := range Ranges {
+		tr := newNatiral()
+		for _, i := range r {
+			tr.Ins(i, i)
+		}
+		for _, from := range testBounds() {
+			for _, to := range testBounds() {
+				var want []interface{}
+				for i := keyMin; i <= keyMax; i++ {
+					if inBounds(from, to, i) {
+						want = append(want, i)
+					}
+				}
+				var got []interface{}
+				tr.AscendRange(from, to, func(k, v interface{}) bool {
+					got = append(got, k)
+					return true
+				})
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Fatal("wrong range", from, to, got, want, rs(r))
+				}
+			}
+		}
+	}
+}
+
+func TestTree_DescendRange(t *testing.T) {
+	// DescendRange(from, to Bound, descendFunc WalkFunc)
+	for _, r := range Ranges {
+		tr := newNatiral()
+		for _, i := range r {
+			tr.Ins(i, i)
+		}
+		for _, from := range testBounds() {
+			for _, to := range testBounds() {
+				var want []interface{}
+				for i := keyMax; i >= keyMin; i-- {
+					if inBounds(to, from, i) {
+						want = append(want, i)
+					}
+				}
+				var got []interface{}
+				tr.DescendRange(from, to, func(k, v interface{}) bool {
+					got = append(got, k)
+					return true
+				})
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Fatal("wrong range", from, to, got, want, rs(r))
+				}
+			}
+		}
+	}
+}
+
+// testNearest checks the near of a tree of even keys, expected
+// key is the least or the greatest key fitting given key
+func testNearest(t *testing.T, name string,
+	near func(tr *Tree, key interface{}) (k, v interface{}, ok bool),
+	fits func(k, i int) bool, greatest bool) {
+
+	for _, r := range Ranges {
+		tr := newNatiral()
+		for _, i := range r {
+			if i%2 == 0 {
+				tr.Ins(i, i)
+			}
+		}
+		for i := keyMin - 2; i <= keyMax+2; i++ {
+			var want = -1 // no one
+			for k := keyMin; k <= keyMax; k += 2 {
+				if fits(k, i) && (want < 0 || greatest) {
+					want = k
+				}
+			}
+			var k, v, ok = near(tr, i)
+			if want < 0 {
+				if ok || k != nil || v != nil {
+					t.Fatal(name, i, "got", k, v, "want nothing", rs(r))
+				}
+			} else if !ok || k != want || v != want {
+				t.Fatal(name, i, "got", k, v, ok, "want", want, rs(r))
+			}
+		}
+	}
+}
+
+func TestTree_Floor(t *testing.T) {
+	// Floor(key interface{}) (k, v interface{}, ok bool)
+	testNearest(t, "Floor", (*Tree).Floor, func(k, i int) bool {
+		return k <= i
+	}, true)
+}
+
+func TestTree_Ceiling(t *testing.T) {
+	// Ceiling(key interface{}) (k, v interface{}, ok bool)
+	testNearest(t, "Ceiling", (*Tree).Ceiling, func(k, i int) bool {
+		return k >= i
+	}, false)
+}
+
+func TestTree_Lower(t *testing.T) {
+	// Lower(key interface{}) (k, v interface{}, ok bool)
+	testNearest(t, "Lower", (*Tree).Lower, func(k, i int) bool {
+		return k < i
+	}, true)
+}
+
+func TestTree_Higher(t *testing.T) {
+	// Higher(key interface{}) (k, v interface{}, ok bool)
+	testNearest(t, "Higher", (*Tree).Higher, func(k, i int) bool {
+		return k > i
+	}, false)
+}
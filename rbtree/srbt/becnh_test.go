@@ -85,10 +85,10 @@ func BenchmarkTree_Get(b *testing.B) {
 			b.ReportAllocs()
 		})
 		b.Run("missing random"+ns, func(b *testing.B) {
-			var rr = randomRange(b.N, n+b.N)
+			var rr = randomRange(n, 2*n)
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				if tree.Get(rr[i]) == true {
+				if tree.Get(rr[i%n]) == true {
 					b.Error("got item not exists")
 				}
 			}
@@ -96,18 +96,18 @@ func BenchmarkTree_Get(b *testing.B) {
 		})
 		b.Run("found successively"+ns, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				if tree.Get(i) == true {
-					b.Error("got item not exists")
+				if tree.Get(i%n) == false {
+					b.Error("missing item")
 				}
 			}
 			b.ReportAllocs()
 		})
-		b.Run("missing random"+ns, func(b *testing.B) {
+		b.Run("found random"+ns, func(b *testing.B) {
 			var rr = randomRange(0, n)
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				if tree.Get(rr[i]) == true {
-					b.Error("got item not exists")
+				if tree.Get(rr[i%n]) == false {
+					b.Error("missing item")
 				}
 			}
 			b.ReportAllocs()
@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+//go:build go1.23
+
+package srbt
+
+import (
+	"iter"
+)
+
+// All returns iterator over items of the Tree in ascending
+// order. The Tree must not be changed during the iteration.
+// Items of the Tree are keys and values at the same time,
+// thus an iterator yields every item twice, like iterators
+// of key-value trees do.
+//
+//	for item := range tree.All() {
+//	    // use the item
+//	}
+func (t *Tree) All() iter.Seq2[interface{}, interface{}] {
+	return func(yield func(k, v interface{}) bool) {
+		var c = t.Cursor()
+		for ok := c.First(); ok && yield(c.Key(), c.Value()); ok = c.Next() {
+		}
+	}
+}
+
+// Backward returns iterator over items of the Tree in
+// descending order.
+func (t *Tree) Backward() iter.Seq2[interface{}, interface{}] {
+	return func(yield func(k, v interface{}) bool) {
+		var c = t.Cursor()
+		for ok := c.Last(); ok && yield(c.Key(), c.Value()); ok = c.Prev() {
+		}
+	}
+}
+
+// Range returns iterator over items from the lower bound
+// from to the upper bound to in ascending order.
+func (t *Tree) Range(from, to Bound) iter.Seq2[interface{}, interface{}] {
+	return func(yield func(k, v interface{}) bool) {
+		t.AscendRange(from, to, func(item interface{}) bool {
+			return yield(item, item)
+		})
+	}
+}
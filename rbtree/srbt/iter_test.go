@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Do What
+// The Fuck You Want To Public License, Version 2, as published by
+// Sam Hocevar. See LICENSE file for more details or see below.
+//
+
+//
+//        DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//                    Version 2, December 2004
+//
+// Copyright (C) 2004 Sam Hocevar <sam@hocevar.net>
+//
+// Everyone is permitted to copy and distribute verbatim or modified
+// copies of this license document, and changing it is allowed as long
+// as the name is changed.
+//
+//            DO WHAT THE FUCK YOU WANT TO PUBLIC LICENSE
+//   TERMS AND CONDITIONS FOR COPYING, DISTRIBUTION AND MODIFICATION
+//
+//  0. You just DO WHAT THE FUCK YOU WANT TO.
+//
+
+//go:build go1.23
+
+package srbt
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/logrusorgru/gods/bound"
+)
+
+func TestTree_All(t *testing.T) {
+	// All() iter.Seq2[interface{}, interface{}]
+	var tree = newNatural()
+	for i := 1; i <= 100; i++ {
+		tree.Ins(i)
+	}
+	var item = 1
+	for it, v := range tree.All() {
+		if it != item || v != item {
+			t.Fatal("wrong item", it, item)
+		}
+		item++
+	}
+	if item != 101 {
+		t.Fatal("wrong end", item)
+	}
+	if items := maps.Collect(tree.All()); len(items) != tree.Size() {
+		t.Error("wrong collected", len(items), tree.Size())
+	}
+	// shuffled items
+	tree = newNatural()
+	for _, i := range []int{67, 27, 13, 41, 25, 15} {
+		tree.Ins(i)
+	}
+	var items []interface{}
+	for it := range tree.All() {
+		items = append(items, it)
+	}
+	if fmt.Sprint(items) != "[13 15 25 27 41 67]" {
+		t.Error("wrong items", items)
+	}
+}
+
+func TestTree_Backward(t *testing.T) {
+	// Backward() iter.Seq2[interface{}, interface{}]
+	var tree = newNatural()
+	for i := 1; i <= 100; i++ {
+		tree.Ins(i)
+	}
+	var item = 100
+	for it := range tree.Backward() {
+		if it != item {
+			t.Fatal("wrong item", it, item)
+		}
+		if item--; item < 90 {
+			break
+		}
+	}
+	if item != 89 {
+		t.Fatal("wrong end", item)
+	}
+}
+
+func TestTree_Range(t *testing.T) {
+	// Range(from, to Bound) iter.Seq2[interface{}, interface{}]
+	var tree = newNatural()
+	for i := 1; i <= 100; i++ {
+		tree.Ins(2 * i)
+	}
+	for _, rg := range []struct {
+		from, to    Bound
+		first, last int
+	}{
+		{bound.Unbounded, bound.Unbounded, 2, 200},
+		{bound.Inclusive(9), bound.Unbounded, 10, 200},
+		{bound.Unbounded, bound.Inclusive(21), 2, 20},
+		{bound.Exclusive(10), bound.Exclusive(20), 12, 18},
+		{bound.Inclusive(20), bound.Inclusive(20), 20, 20},
+	} {
+		var item = rg.first
+		for it := range tree.Range(rg.from, rg.to) {
+			if it != item {
+				t.Fatal("wrong item", it, item, rg)
+			}
+			item += 2
+		}
+		if item != rg.last+2 {
+			t.Error("wrong end", item, rg)
+		}
+	}
+}
@@ -32,8 +32,9 @@ package srbt
 
 import (
 	"fmt"
-	"github.com/disiqueira/gotree"
-	"github.com/logrusorgru/aurora"
+
+	"github.com/logrusorgru/gods/bound"
+	"github.com/logrusorgru/gods/printer"
 )
 
 // LessFunc
@@ -45,6 +46,10 @@ type EqualFunc func(a, b interface{}) bool
 // ZeroFunc return true if given item is zero.
 type ZeroFunc func(a interface{}) bool
 
+// Bound of a range of the AscendRange or the DescendRange, use
+// bound.Inclusive, bound.Exclusive or bound.Unbounded.
+type Bound = bound.Bound
+
 type color bool
 
 const (
@@ -87,35 +92,6 @@ func (n *node) oppositeChild(x *node) *node {
 	return n.left
 }
 
-// successor for this deleted node with
-// path to it; the n is not the sentinel
-func (n *node) successor(br []*node) (sr []*node, x *node) {
-	var end = &sentinel
-	sr = br
-	for x = n.left; x.right != end; x = x.right {
-		sr = append(sr, x)
-	}
-	return
-}
-
-// BST-replacement for this deleted node with
-// relative path; then n is not the sentinel
-func (n *node) replacement(br []*node) (sr []*node, x *node) {
-	var end = &sentinel
-	br = push(br, n) // this
-	switch {
-	case n.left != end && n.right != end:
-		return n.successor(br) // min of the right
-	case n.left != end:
-		return br, n.left // has the left only
-	case n.right != end:
-		return br, n.right // has the right only
-	default:
-		// no successors
-	}
-	return br, end
-}
-
 func (n *node) insert(less LessFunc, x *node) {
 	if less(x.item, n.item) == true {
 		n.left = x
@@ -213,30 +189,24 @@ func (t *Tree) replaceChild(n *node, old, new *node) {
 	}
 }
 
+// the x already popped from the branch, i.e. last
+// element of the branch is parent of the x; if the
+// branch is empty, then right node of the x becomes
+// root of the Tree
 func (t *Tree) rotateLeft(br []*node, x *node) {
-	var d, pivot *node
-	br, d = pop(br)
-	pivot = x.right
+	var _, d = pop(br)
+	var pivot = x.right
 	t.replaceChild(d, x, pivot) // the pivot might become root
-	if d != nil {
-		d.replaceChild(x, pivot)
-	}
 	x.right = pivot.left
 	pivot.left = x
 }
 
-// the x already popped from the branch, i.e. last
-// element of the branch is parent of the x; if the
-// branch is empty, then right node of the x becomes
-// root of the Tree;
+// the same as the rotateLeft, but left node of
+// the x goes up
 func (t *Tree) rotateRight(br []*node, x *node) {
-	var d, pivot *node
-	br, d = pop(br)
-	pivot = x.left
+	var _, d = pop(br)
+	var pivot = x.left
 	t.replaceChild(d, x, pivot) // the pivot might become root
-	if d != nil {
-		d.replaceChild(x, pivot)
-	}
 	x.left = pivot.right
 	pivot.right = x
 }
@@ -413,134 +383,75 @@ func (t *Tree) InsEx(item interface{}) (ok bool) {
 	return // false, not found
 }
 
-func (t *Tree) fixDoubleBlack(br []*node, n *node) {
-	if t.isRoot(n) == true {
-		return
-	}
-	var (
-		d, s *node
-		end  = &sentinel
-	)
-	// the n is not root, then the d is not nil
-	br, d = pop(br)
-	printNode("fdb N", n)
-	printNode("fdb D", d)
-	if s = d.oppositeChild(n); s == end {
-		printNode("fdb S [end]", s)
-		t.fixDoubleBlack(br, d) // push up
-		return
-	}
-	printNode("fdb S", s)
-	if s.isRed() == true {
-		d.color, s.color = red, black
-		if d.left == s {
-			t.rotateRight(br, d)
-		} else {
-			t.rotateLeft(br, d)
-		}
-		t.fixDoubleBlack(push(br, d), n)
-		return
-	}
-	// s is black
-	if s.left.isRed() || s.right.isRed() {
-		if s.left.isRed() == true {
-			printNode("fdb S left (red)", s.left)
-			if d.left == s {
-				s.left.color, s.color = s.color, d.color
-				println("br", len(br))
-				printNode("path", br[0])
-				t.rotateRight(br, d)
-			} else {
-				s.right.color, s.color = s.color, d.color
+// fix double black x, the branch is ancestors of the x;
+// the x can be the sentinel, then its sibling is not
+func (t *Tree) fixDoubleBlack(br []*node, x *node) {
+	var d, s *node // dad, sibling
+	for !t.isRoot(x) && x.isBlack() {
+		br, d = pop(br)
+		if d.left == x {
+			if s = d.right; s.isRed() {
+				s.color, d.color = black, red
 				t.rotateLeft(br, d)
+				br, s = push(br, s), d.right
+			}
+			if s.left.isBlack() && s.right.isBlack() {
+				s.color, x = red, d // push up
+				continue
+			}
+			if s.right.isBlack() {
+				s.left.color, s.color = black, red
+				t.rotateRight(push(br, d), s)
+				s = d.right
 			}
+			s.color, d.color, s.right.color = d.color, black, black
+			t.rotateLeft(br, d)
 		} else {
-			// the s.right is red (is not the sentinel)
-			if d.left == s {
-				s.right.color = d.color
-				t.rotateLeft(push(br, d), s)
+			if s = d.left; s.isRed() {
+				s.color, d.color = black, red
 				t.rotateRight(br, d)
-			} else {
-				s.right.color, s.color = s.color, d.color
-				t.rotateLeft(br, d)
+				br, s = push(br, s), d.left
+			}
+			if s.left.isBlack() && s.right.isBlack() {
+				s.color, x = red, d // push up
+				continue
+			}
+			if s.left.isBlack() {
+				s.right.color, s.color = black, red
+				t.rotateLeft(push(br, d), s)
+				s = d.left
 			}
+			s.color, d.color, s.left.color = d.color, black, black
+			t.rotateRight(br, d)
 		}
-		d.color = black
-		return
-	}
-	s.color = red
-	if d.isBlack() == true {
-		t.fixDoubleBlack(br, d)
-	} else {
-		d.color = black
+		return // done
 	}
-}
-
-func printNode(pfx string, n *node) {
-	if n == nil {
-		println(pfx, "<nil>")
-	} else if n == &sentinel {
-		println(pfx, "[nil]")
-	} else if n.left == &sentinel && n.right == &sentinel {
-		println(pfx, n.item.(int))
-	} else if n.left == &sentinel && n.right != &sentinel {
-		println(pfx, n.item.(int), "[nil]", n.right.item.(int))
-	} else if n.left != &sentinel && n.right == &sentinel {
-		println(pfx, n.item.(int), n.left.item.(int), "[nil]")
-	} else if n.left != &sentinel && n.right != &sentinel {
-		println(pfx, n.item.(int), n.left.item.(int), n.right.item.(int))
+	if !x.isSentinel() {
+		x.color = black
 	}
 }
 
 // delete given node with branch of its ancestors
 func (t *Tree) delete(br []*node, n *node) {
-	var (
-		sr   []*node
-		d, r *node
-		end  = &sentinel
-	)
-	sr, r = n.replacement(br)
-	br, d = pop(br)
-	printNode("del N", n)
-	printNode("del R", r)
-	printNode("del D", d)
-	if r == end {
-		if d == nil {
-			t.root = end // the n in root, the Tree becomes empty
-			return
-		}
-		// the n is not root, then the d is not nil
-		if r.isBlack() && n.isBlack() == true {
-			// the d popped out
-			t.fixDoubleBlack(push(br, d), n)
-		} else {
-			// sibling
-			if s := d.oppositeChild(n); s != end {
-				s.color = red
-			}
+	var end = &sentinel
+	if n.left != end && n.right != end {
+		// copy min of the right and delete it instead
+		var x = n.right
+		for br = push(br, n); x.left != end; x = x.left {
+			br = push(br, x)
 		}
-		d.replaceChild(n, end)
-		return
+		n.item, n = x.item, x
 	}
-	// the r is not sentinel
-	if n.left == end || n.right == end {
-		if t.isRoot(n) == true {
-			n.item = r.item
-			n.left, n.right = end, end
-		} else {
-			// the n is not root, then d is not nil
-			d.replaceChild(n, r)
-			if n.isBlack() && r.isBlack() == true {
-				t.fixDoubleBlack(sr, r)
-			} else {
-				r.color = black
-			}
-		}
-		return
+	// the n has one child at most
+	var c = n.left
+	if c == end {
+		c = n.right
+	}
+	var _, d = pop(br)
+	t.replaceChild(d, n, c)
+	if n.isBlack() {
+		t.fixDoubleBlack(br, c) // the c is red or double black
 	}
-	n.item = r.item
-	t.delete(sr, r)
-	return
 }
 
 func (t *Tree) Del(item interface{}) (ok bool) {
@@ -566,6 +477,9 @@ func (t *Tree) Clear() {
 
 func (t *Tree) min() (n *node) {
 	var end = &sentinel
+	if t.root == end {
+		return nil // empty
+	}
 	for n = t.root; n.left != end; n = n.left {
 	}
 	return
@@ -583,6 +497,9 @@ func (t *Tree) Min() (interface{}, bool) {
 
 func (t *Tree) max() (n *node) {
 	var end = &sentinel
+	if t.root == end {
+		return nil // empty
+	}
 	for n = t.root; n.right != end; n = n.right {
 	}
 	return
@@ -598,6 +515,74 @@ func (t *Tree) Max() (interface{}, bool) {
 	return n.item, true
 }
 
+// the first node of a range of the lower bound, unlike
+// the lowerBranch it doesn't track ancestors of the node
+func (t *Tree) firstNode(lo Bound) (s *node) {
+	var less, end = t.less, &sentinel
+	for n := t.root; n != end; {
+		if lo.Below(less, n.item) {
+			n = n.right
+		} else {
+			s, n = n, n.left
+		}
+	}
+	return
+}
+
+// the last node of a range of the upper bound, unlike
+// the upperBranch it doesn't track ancestors of the node
+func (t *Tree) lastNode(hi Bound) (s *node) {
+	var less, end = t.less, &sentinel
+	for n := t.root; n != end; {
+		if hi.Above(less, n.item) {
+			n = n.left
+		} else {
+			s, n = n, n.right
+		}
+	}
+	return
+}
+
+// Floor returns the greatest item less than or equal to given
+// one, or (nil, false) if there is no such item.
+func (t *Tree) Floor(item interface{}) (interface{}, bool) {
+	var n = t.lastNode(bound.Inclusive(item))
+	if n == nil {
+		return nil, false // not found
+	}
+	return n.item, true
+}
+
+// Ceiling returns the least item greater than or equal to given
+// one, or (nil, false) if there is no such item.
+func (t *Tree) Ceiling(item interface{}) (interface{}, bool) {
+	var n = t.firstNode(bound.Inclusive(item))
+	if n == nil {
+		return nil, false // not found
+	}
+	return n.item, true
+}
+
+// Lower returns the greatest item less than given
+// one, or (nil, false) if there is no such item.
+func (t *Tree) Lower(item interface{}) (interface{}, bool) {
+	var n = t.lastNode(bound.Exclusive(item))
+	if n == nil {
+		return nil, false // not found
+	}
+	return n.item, true
+}
+
+// Higher returns the least item greater than given
+// one, or (nil, false) if there is no such item.
+func (t *Tree) Higher(item interface{}) (interface{}, bool) {
+	var n = t.firstNode(bound.Exclusive(item))
+	if n == nil {
+		return nil, false // not found
+	}
+	return n.item, true
+}
+
 type WalkFunc func(item interface{}) (next bool)
 
 // Walk over all items of the Tree without any order.
@@ -611,9 +596,9 @@ func (t *Tree) Walk(walkFunc WalkFunc) {
 
 		end = &sentinel
 	)
-	for n != end && len(rs) > 0 {
+	for n != end || len(rs) > 0 {
 		for n != end {
-			if walkFunc(n) == false {
+			if walkFunc(n.item) == false {
 				return
 			}
 			if n.right != end {
@@ -645,311 +630,258 @@ func (t *Tree) maxBranch() (br []*node, n *node) {
 	return
 }
 
-// [from, +inf)
-func (t *Tree) ascendFrom(from interface{}, ascendFunc WalkFunc) {
-	var (
-		br, n = t.findNodeBranch(from)
-		end   = &sentinel
-	)
-	if n == nil {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
-	}
-	for n != end {
-		if ascendFunc(n) == false {
-			return
-		}
-		if n.right != end {
-			n = n.right
-			for n.left != end {
-				br = push(br, n)
-				n = n.left
-			}
-			continue // the n pushed and popped virtually
-		}
-		br, n = pop(br)
+// the zero item is unbounded, other items are inclusive
+func (t *Tree) zeroBound(item interface{}) Bound {
+	if t.zero(item) {
+		return bound.Unbounded
 	}
+	return bound.Inclusive(item)
 }
 
-// (-inf, to]
-func (t *Tree) ascendTo(to interface{}, ascendFunc WalkFunc) {
+// AscendRange iterates items of the Tree in ascending order
+// from the lower bound from to the upper bound to. Unlike the
+// Ascend, a bound can be any item, including zero one.
+func (t *Tree) AscendRange(from, to Bound, ascendFunc WalkFunc) {
 	var (
-		br, n = t.minBranch()
-
+		br, n = t.lowerBranch(from)
 		less  = t.less
-		equal = t.equal
-
-		end = &sentinel
 	)
-	if n == end {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
-	}
-	for n != end {
-		// TODO (kostyarin): the less && equal
-		if less(to, n.item) == false && equal(to, n.item) == false {
+	for ; n != nil; br, n = next(br, n) {
+		if to.Above(less, n.item) {
 			return // that's all
 		}
-		if ascendFunc(n) == false {
+		if !ascendFunc(n.item) {
 			return
 		}
-		if n.right != end {
-			n = n.right
-			for n.left != end {
-				br = push(br, n)
-				n = n.left
-			}
-			continue // the n pushed and popped virtually
-		}
-		br, n = pop(br)
 	}
 }
 
-// [from, to]
-func (t *Tree) ascendFromTo(from, to interface{}, ascendFunc WalkFunc) {
-	var (
-		br, n = t.findNodeBranch(from)
+// Ascend iterates items of the Tree in ascending order. The
+// ZeroFunc used to determine ascending range: a zero from or
+// to is unbounded, other items are inclusive bounds. See also
+// the AscendRange.
+func (t *Tree) Ascend(from, to interface{}, ascendFunc WalkFunc) {
+	t.AscendRange(t.zeroBound(from), t.zeroBound(to), ascendFunc)
+}
 
+// DescendRange iterates items of the Tree in descending order
+// from the upper bound from to the lower bound to. Unlike the
+// Descend, a bound can be any item, including zero one.
+func (t *Tree) DescendRange(from, to Bound, descendFunc WalkFunc) {
+	var (
+		br, n = t.upperBranch(from)
 		less  = t.less
-		equal = t.equal
-
-		end = &sentinel
 	)
-	if n == nil {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
-	}
-	for n != end {
-		if less(to, n.item) && equal(to, n.item) == false {
+	for ; n != nil; br, n = prev(br, n) {
+		if to.Below(less, n.item) {
 			return // that's all
 		}
-		if ascendFunc(n) == false {
+		if !descendFunc(n.item) {
 			return
 		}
-		if n.right != end {
-			n = n.right
-			for n.left != end {
-				br = push(br, n)
-				n = n.left
-			}
-			continue // the n pushed and popped virtually
-		}
-		br, n = pop(br)
 	}
 }
 
-// (-inf, +inf)
-func (t *Tree) ascend(ascendFunc WalkFunc) {
-	var (
-		br, n = t.minBranch()
-		end   = &sentinel
-	)
-	if n == end {
-		if len(br) == 0 {
-			return
+// Descend iterates items of the Tree in descending order. The
+// ZeroFunc used to determine descending range like the Ascend
+// does. See also the DescendRange.
+func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
+	t.DescendRange(t.zeroBound(from), t.zeroBound(to), descendFunc)
+}
+
+// next node in ascending order and branch of its ancestors,
+// it returns nil node at the end
+func next(br []*node, n *node) ([]*node, *node) {
+	var end = &sentinel
+	if n.right != end {
+		for br, n = push(br, n), n.right; n.left != end; n = n.left {
+			br = push(br, n)
 		}
-		br, n = pop(br)
+		return br, n
 	}
-	for n != end {
-		if ascendFunc(n) == false {
-			return
-		}
-		if n.right != end {
-			n = n.right
-			for n.left != end {
-				br = push(br, n)
-				n = n.left
-			}
-			continue // the n pushed and popped virtually
+	var d *node
+	for {
+		if br, d = pop(br); d == nil || d.left == n {
+			return br, d
 		}
-		br, n = pop(br)
+		n = d
 	}
 }
 
-func (t *Tree) Ascend(from, to interface{}, ascendFunc WalkFunc) {
-	// zero function
-	switch zero := t.zero; {
-	case zero(from) == true: // (-inf, to] or (-inf, +inf)
-		if zero(to) == true {
-			t.ascend(ascendFunc) // (-inf, +inf)
-		} else {
-			t.ascendTo(to, ascendFunc) // (-inf, to]
+// prev node in ascending order and branch of its ancestors,
+// it returns nil node at the beginning
+func prev(br []*node, n *node) ([]*node, *node) {
+	var end = &sentinel
+	if n.left != end {
+		for br, n = push(br, n), n.left; n.right != end; n = n.right {
+			br = push(br, n)
 		}
-	case zero(to) == true: // [from, +inf)
-		t.ascendFrom(from, ascendFunc)
-	default: // [from, to]
-		t.ascendFromTo(from, to, ascendFunc)
+		return br, n
+	}
+	var d *node
+	for {
+		if br, d = pop(br); d == nil || d.right == n {
+			return br, d
+		}
+		n = d
 	}
 }
 
-// [from, -inf) (reversed)
-func (t *Tree) descendFrom(from interface{}, descendFunc WalkFunc) {
+// the first node of a range of the lower bound and branch
+// of its ancestors, the node is nil if the range is empty
+func (t *Tree) lowerBranch(lo Bound) (br []*node, s *node) {
 	var (
-		br, n = t.findNodeBranch(from)
+		less  = t.less
 		end   = &sentinel
+		depth int
 	)
-	if n == nil {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
-	}
-	for n != end {
-		if descendFunc(n) == false {
-			return
-		}
-		if n.left != end {
+	for n := t.root; n != end; {
+		if lo.Below(less, n.item) {
+			br = push(br, n)
+			n = n.right
+		} else {
+			s, depth = n, len(br)
+			br = push(br, n)
 			n = n.left
-			for n.right != end {
-				br = push(br, n)
-				n = n.right
-			}
-			continue // the n pushed and popped virtually
 		}
-		br, n = pop(br)
 	}
+	return br[:depth], s
 }
 
-// (+inf, to] (reversed)
-func (t *Tree) descendTo(to interface{}, descendFunc WalkFunc) {
+// the last node of a range of the upper bound and branch
+// of its ancestors, the node is nil if the range is empty
+func (t *Tree) upperBranch(hi Bound) (br []*node, s *node) {
 	var (
-		br, n = t.maxBranch()
-
 		less  = t.less
-		equal = t.equal
-
-		end = &sentinel
+		end   = &sentinel
+		depth int
 	)
-	if n == end {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
-	}
-	for n != end {
-		// TODO (kostyarin): the less && equal
-		if less(to, n.item) == false && equal(to, n.item) == false {
-			return // that's all
-		}
-		if descendFunc(n) == false {
-			return
-		}
-		if n.left != end {
+	for n := t.root; n != end; {
+		if hi.Above(less, n.item) {
+			br = push(br, n)
 			n = n.left
-			for n.right != end {
-				br = push(br, n)
-				n = n.right
-			}
-			continue // the n pushed and popped virtually
+		} else {
+			s, depth = n, len(br)
+			br = push(br, n)
+			n = n.right
 		}
-		br, n = pop(br)
 	}
+	return br[:depth], s
 }
 
-// [from, to] (reversed)
-func (t *Tree) descendFromTo(from, to interface{}, descendFunc WalkFunc) {
-	var (
-		br, n = t.findNodeBranch(from)
+// A Cursor is position in the Tree. Unlike the Ascend and
+// the Descend, a Cursor can pause, step back and forth, and
+// iterate many trees at the same time. A Cursor keeps branch
+// of ancestors of its item. A change of the Tree invalidates
+// its cursors. A new Cursor is not valid, use First, Last or
+// Seek to point it to an item.
+type Cursor struct {
+	t  *Tree
+	br []*node // ancestors of the n
+	n  *node
+}
 
-		less  = t.less
-		equal = t.equal
+// Cursor of the Tree.
+func (t *Tree) Cursor() *Cursor {
+	return &Cursor{t: t}
+}
 
-		end = &sentinel
-	)
-	if n == nil {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
+// First moves the Cursor to the min item. It returns
+// false if the Tree is empty.
+func (c *Cursor) First() bool {
+	if c.br, c.n = c.t.minBranch(); c.n.isSentinel() {
+		c.br, c.n = nil, nil // empty tree
 	}
-	for n != end {
-		// TODO (kostyarin): the less && equal
-		if less(to, n.item) == false && equal(to, n.item) == false {
-			return // that's all
-		}
-		if descendFunc(n) == false {
-			return
-		}
-		if n.left != end {
-			n = n.left
-			for n.right != end {
-				br = push(br, n)
-				n = n.right
-			}
-			continue // the n pushed and popped virtually
-		}
-		br, n = pop(br)
+	return c.n != nil
+}
+
+// Last moves the Cursor to the max item. It returns
+// false if the Tree is empty.
+func (c *Cursor) Last() bool {
+	if c.br, c.n = c.t.maxBranch(); c.n.isSentinel() {
+		c.br, c.n = nil, nil // empty tree
 	}
+	return c.n != nil
 }
 
-// (-inf, +inf) (reversed)
-func (t *Tree) descend(descendFunc WalkFunc) {
-	var (
-		br, n = t.maxBranch()
-		end   = &sentinel
-	)
-	if n == end {
-		if len(br) == 0 {
-			return
-		}
-		br, n = pop(br)
+// Seek moves the Cursor to the first item greater than or
+// equal to given. It returns false if there is no such item.
+func (c *Cursor) Seek(item interface{}) bool {
+	c.br, c.n = c.t.lowerBranch(bound.Inclusive(item))
+	return c.n != nil
+}
+
+// Next moves the Cursor to the next item in ascending order.
+// It returns false if the Cursor reaches end of the Tree or
+// it's not valid.
+func (c *Cursor) Next() bool {
+	if c.n != nil {
+		c.br, c.n = next(c.br, c.n)
 	}
-	for n != end {
-		if descendFunc(n) == false {
-			return
-		}
-		if n.left != end {
-			n = n.left
-			for n.right != end {
-				br = push(br, n)
-				n = n.right
-			}
-			continue // the n pushed and popped virtually
-		}
-		br, n = pop(br)
+	return c.n != nil
+}
+
+// Prev moves the Cursor to the previous item in ascending
+// order. It returns false if the Cursor reaches beginning of
+// the Tree or it's not valid.
+func (c *Cursor) Prev() bool {
+	if c.n != nil {
+		c.br, c.n = prev(c.br, c.n)
 	}
+	return c.n != nil
 }
 
-func (t *Tree) Descend(from, to interface{}, descendFunc WalkFunc) {
-	// zero function
-	switch zero := t.zero; {
-	case zero(from) == true: // (-inf, to] or (-inf, +inf)
-		if zero(to) == true {
-			t.descend(descendFunc) // (-inf, +inf)
-		} else {
-			t.descendTo(to, descendFunc) // (-inf, to]
-		}
-	case zero(to) == true: // [from, +inf)
-		t.descendFrom(from, descendFunc)
-	default: // [from, to]
-		t.descendFromTo(from, to, descendFunc)
+// Valid returns true if the Cursor points to an item.
+func (c *Cursor) Valid() bool {
+	return c.n != nil
+}
+
+// Key returns current item or nil if the Cursor
+// is not valid. Items of the Tree are keys and
+// values at the same time.
+func (c *Cursor) Key() interface{} {
+	if c.n == nil {
+		return nil
 	}
+	return c.n.item
+}
+
+// Value returns current item or nil if the Cursor
+// is not valid, it's the same as the Key.
+func (c *Cursor) Value() interface{} {
+	return c.Key()
 }
 
-func ansiColor(n *node) aurora.Color {
-	if n.color == black {
-		return aurora.BlueFg | aurora.BoldFm
+// Printer prints a Tree, use printer.New
+// to print to string
+type Printer = printer.Printer
+
+func (c color) String() string {
+	if c == red {
+		return "R"
 	}
-	return aurora.RedFg
+	return "B"
 }
 
-func (n *node) print(pr gotree.Tree) {
-	if n == &sentinel {
-		//pr.Add(aurora.Colorize("[nil]", ansiColor(n)).String())
+// print subtree of the n
+func (n *node) print(p Printer) {
+	p = p.Add(fmt.Sprintf("[%s] %v", n.color, n.item))
+	if n.left.isSentinel() && n.right.isSentinel() {
 		return
 	}
-	np := pr.Add(aurora.Colorize(n.item, ansiColor(n)).String())
-	n.left.print(np)
-	n.right.print(np)
+	for _, c := range []*node{n.left, n.right} {
+		if c.isSentinel() {
+			p.Add("nil") // keep left and right
+			continue
+		}
+		c.print(p)
+	}
 }
 
-func (t *Tree) Print(pr gotree.Tree) {
-	pr.Add(fmt.Sprintf("size: %d", t.size))
-	t.root.print(pr)
+// Print the Tree to given Printer. Labels of nodes
+// are colours and items, like "[R] 5".
+func (t *Tree) Print(p Printer) {
+	if !t.root.isSentinel() {
+		t.root.print(p)
+	}
 }
@@ -26,7 +26,11 @@
 package srbt
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
+
+	"github.com/logrusorgru/gods/bound"
 )
 
 func newNatural() *Tree {
@@ -47,7 +51,7 @@ func testee() []int {
 	return []int{
 		100, 101, 102, 103, 104, 105, 106, 107, 108, 109, 110,
 		1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
-		80, 81, 82, 83, 84, 85, 86, 86, 87, 88, 89, 90,
+		80, 81, 82, 83, 84, 85, 86, 87, 88, 89, 90,
 		200, 201, 202, 203, 204, 205, 206, 207, 208, 209, 210,
 		50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60,
 	}
@@ -84,7 +88,7 @@ func TestTree_Get(t *testing.T) {
 		}
 	}
 	for _, item := range testee() {
-		if tree.Get(item) == false {
+		if tree.Get(item) == true {
 			t.Errorf("got deleted item %d", item)
 		}
 	}
@@ -134,7 +138,7 @@ func TestTree_Del(t *testing.T) {
 	var tree = newNatural()
 	for _, item := range testee() {
 		if tree.Del(item) == true {
-			t.Errorf("new item created, but should not %d", item)
+			t.Errorf("deleted item not exist %d", item)
 		}
 	}
 	for _, item := range testee() {
@@ -142,10 +146,14 @@ func TestTree_Del(t *testing.T) {
 			t.Errorf("new item inserted with false %d", item)
 		}
 	}
-	for _, item := range testee() {
-		if tree.InsEx(item) == false {
-			t.Errorf("item not overwritten %d", item)
+	for i, item := range testee() {
+		if tree.Del(item) == false {
+			t.Errorf("delete item with false %d", item)
 		}
+		if tree.Size() != len(testee())-i-1 {
+			t.Errorf("wrong size %d after delete %d", tree.Size(), item)
+		}
+		tree.check(t)
 	}
 }
 
@@ -159,14 +167,51 @@ func TestTree_Clear(t *testing.T) {
 
 func TestTree_Min(t *testing.T) {
 	// Min() (interface{}, bool)
+	var tree = newNatural()
+	if item, ok := tree.Min(); ok || item != nil {
+		t.Error("min of empty tree", item)
+	}
+	for _, item := range testee() {
+		tree.Ins(item)
+	}
+	if item, ok := tree.Min(); !ok || item != 1 {
+		t.Error("wrong min", item, ok)
+	}
 }
 
 func TestTree_Max(t *testing.T) {
 	// Max() (interface{}, bool)
+	var tree = newNatural()
+	if item, ok := tree.Max(); ok || item != nil {
+		t.Error("max of empty tree", item)
+	}
+	for _, item := range testee() {
+		tree.Ins(item)
+	}
+	if item, ok := tree.Max(); !ok || item != 210 {
+		t.Error("wrong max", item, ok)
+	}
 }
 
 func TestTree_Walk(t *testing.T) {
 	// Walk(walkFunc WalkFunc)
+	var tree = newNatural()
+	for _, item := range testee() {
+		tree.Ins(item)
+	}
+	var got = make(map[interface{}]bool)
+	tree.Walk(func(item interface{}) bool {
+		got[item] = true
+		return true
+	})
+	for _, item := range testee() {
+		if !got[item] {
+			t.Error("missing item", item)
+		}
+	}
+	if len(got) != tree.Size() {
+		t.Error("wrong number of items", len(got))
+	}
 }
 
 func TestTree_Ascend(t *testing.T) {
@@ -176,3 +221,225 @@ func TestTree_Ascend(t *testing.T) {
 func TestTree_Descend(t *testing.T) {
 	// Descend(from, to interface{}, descendFunc WalkFunc)
 }
+
+func TestTree_Cursor(t *testing.T) {
+	// Cursor() *Cursor
+	var tree = newNatural()
+	var c = tree.Cursor()
+	if c.Valid() || c.First() || c.Last() || c.Seek(1) || c.Key() != nil ||
+		c.Value() != nil {
+		t.Error("cursor of empty tree is valid")
+	}
+	for i := 1; i <= 100; i++ {
+		tree.Ins(2 * i) // even items 2..200
+	}
+	var item = 2
+	for ok := c.First(); ok; ok = c.Next() {
+		if c.Key() != item {
+			t.Fatalf("wrong item %v, want %d", c.Key(), item)
+		}
+		item += 2
+	}
+	if item != 202 {
+		t.Fatal("wrong end", item)
+	}
+	item = 200
+	for ok := c.Last(); ok; ok = c.Prev() {
+		if c.Key() != item {
+			t.Fatalf("wrong item %v, want %d", c.Key(), item)
+		}
+		item -= 2
+	}
+	if item != 0 || c.Valid() {
+		t.Fatal("wrong beginning", item)
+	}
+	for item = 0; item <= 200; item++ {
+		var want = item + item&1 // next even
+		if want == 0 {
+			want = 2
+		}
+		if !c.Seek(item) || c.Key() != want {
+			t.Fatalf("wrong seek %d: %v, want %d", item, c.Key(), want)
+		}
+		if want > 2 && (!c.Prev() || c.Key() != want-2) {
+			t.Fatalf("can't step back from %d: %v", want, c.Key())
+		}
+	}
+	if c.Seek(201) || c.Valid() {
+		t.Error("seek above max", c.Key())
+	}
+}
+
+func TestTree_AscendRange(t *testing.T) {
+	// AscendRange(from, to Bound, ascendFunc WalkFunc)
+	var tree = newNatural()
+	for i := 0; i <= 10; i++ {
+		tree.Ins(i)
+	}
+	var items []interface{}
+	var collect = func(item interface{}) bool {
+		items = append(items, item)
+		return true
+	}
+	tree.AscendRange(bound.Inclusive(0), bound.Exclusive(3), collect)
+	if fmt.Sprint(items) != "[0 1 2]" {
+		t.Error("wrong ascending range", items)
+	}
+	items = items[:0]
+	tree.Ascend(0, 3, collect) // zero is unbounded
+	if fmt.Sprint(items) != "[0 1 2 3]" {
+		t.Error("wrong ascending range", items)
+	}
+}
+
+func TestTree_DescendRange(t *testing.T) {
+	// DescendRange(from, to Bound, descendFunc WalkFunc)
+	var tree = newNatural()
+	for i := 0; i <= 10; i++ {
+		tree.Ins(i)
+	}
+	var items []interface{}
+	var collect = func(item interface{}) bool {
+		items = append(items, item)
+		return true
+	}
+	tree.DescendRange(bound.Exclusive(3), bound.Unbounded, collect)
+	if fmt.Sprint(items) != "[2 1 0]" {
+		t.Error("wrong descending range", items)
+	}
+	items = items[:0]
+	tree.Descend(10, 8, collect)
+	if fmt.Sprint(items) != "[10 9 8]" {
+		t.Error("wrong descending range", items)
+	}
+}
+
+// testNearest checks the near of a tree of even items, expected
+// item is the least or the greatest item fitting given item
+func testNearest(t *testing.T, name string,
+	near func(tr *Tree, item interface{}) (interface{}, bool),
+	fits func(k, i int) bool, greatest bool) {
+
+	var tree = newNatural()
+	for _, i := range rand.Perm(11) {
+		tree.Ins(2 * i) // shuffled even items 0..20
+	}
+	for i := -2; i <= 22; i++ {
+		var want = -1 // no one
+		for k := 0; k <= 20; k += 2 {
+			if fits(k, i) && (want < 0 || greatest) {
+				want = k
+			}
+		}
+		var item, ok = near(tree, i)
+		if want < 0 {
+			if ok || item != nil {
+				t.Error(name, i, "got", item, "want nothing")
+			}
+		} else if !ok || item != want {
+			t.Error(name, i, "got", item, ok, "want", want)
+		}
+	}
+}
+
+func TestTree_Floor(t *testing.T) {
+	// Floor(item interface{}) (interface{}, bool)
+	testNearest(t, "Floor", (*Tree).Floor, func(k, i int) bool {
+		return k <= i
+	}, true)
+}
+
+func TestTree_Ceiling(t *testing.T) {
+	// Ceiling(item interface{}) (interface{}, bool)
+	testNearest(t, "Ceiling", (*Tree).Ceiling, func(k, i int) bool {
+		return k >= i
+	}, false)
+}
+
+func TestTree_Lower(t *testing.T) {
+	// Lower(item interface{}) (interface{}, bool)
+	testNearest(t, "Lower", (*Tree).Lower, func(k, i int) bool {
+		return k < i
+	}, true)
+}
+
+func TestTree_Higher(t *testing.T) {
+	// Higher(item interface{}) (interface{}, bool)
+	testNearest(t, "Higher", (*Tree).Higher, func(k, i int) bool {
+		return k > i
+	}, false)
+}
+
+// check order of items, size and red-black
+// properties of the tree
+func (t *Tree) check(tb testing.TB) {
+	tb.Helper()
+	if t.root.isRed() || sentinel.isRed() {
+		tb.Fatal("red root or sentinel")
+	}
+	var (
+		size int
+		prev *node
+		walk func(n *node) int
+	)
+	walk = func(n *node) (h int) {
+		if n.isSentinel() {
+			return 1
+		}
+		if n.isRed() && (n.left.isRed() || n.right.isRed()) {
+			tb.Fatal("red node has red child", n.item)
+		}
+		if h = walk(n.left); prev != nil && !t.less(prev.item, n.item) {
+			tb.Fatal("wrong order of items", prev.item, n.item)
+		}
+		prev, size = n, size+1
+		if h != walk(n.right) {
+			tb.Fatal("different black heights", n.item)
+		}
+		if n.isBlack() {
+			h++
+		}
+		return
+	}
+	walk(t.root)
+	if size != t.size {
+		tb.Fatal("wrong size", t.size, "want", size)
+	}
+}
+
+func TestTree_random(t *testing.T) {
+	// Ins, InsNx and Del compared with a set
+	var (
+		tree = newNatural()
+		set  = make(map[int]bool)
+		rnd  = rand.New(rand.NewSource(1))
+	)
+	for i := 0; i < 20000; i++ {
+		var item = rnd.Intn(64) + 1
+		switch rnd.Intn(3) {
+		case 0:
+			if tree.Ins(item) == set[item] {
+				t.Fatal("wrong Ins", item)
+			}
+			set[item] = true
+		case 1:
+			if tree.InsNx(item) == set[item] {
+				t.Fatal("wrong InsNx", item)
+			}
+			set[item] = true
+		default:
+			if tree.Del(item) != set[item] {
+				t.Fatal("wrong Del", item)
+			}
+			delete(set, item)
+		}
+		tree.check(t)
+	}
+	tree.Walk(func(item interface{}) bool {
+		delete(set, item.(int))
+		return true
+	})
+	if len(set) != 0 {
+		t.Fatal("missing items", set)
+	}
+}